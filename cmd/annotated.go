@@ -0,0 +1,92 @@
+/*
+Copyright © 2020 Singee <i@singee.me>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"fmt"
+	"github.com/ImSingee/god/generator"
+	"github.com/ImSingee/god/utils"
+	"github.com/spf13/cobra"
+	"sort"
+)
+
+// runAnnotated 为包中所有带有注释指令的结构体生成代码，例如 //god:getter 会为结构体生成 Getter
+func runAnnotated(cmd *cobra.Command, args []string) error {
+	upToDate, err := useCache(cmd)
+
+	if err != nil || upToDate {
+		return err
+	}
+
+	options := optionsFromViper()
+	options.Annotated = true
+
+	pkg, err := loadPackage(options)
+
+	if err != nil {
+		return err
+	}
+
+	structs := pkg.Structs
+
+	// 按照生成器分组
+	groups := make(map[string]utils.Structs)
+
+	for _, s := range structs.List() {
+		for _, annotation := range s.Annotations {
+			if !generator.Exists(options, annotation.Name) {
+				return fmt.Errorf("%s: unknown generator %s in annotation %s", annotation.Pos, annotation.Name, annotation)
+			}
+
+			if groups[annotation.Name] == nil {
+				groups[annotation.Name] = make(utils.Structs)
+			}
+			groups[annotation.Name][s.Name] = s
+		}
+	}
+
+	err = reportWarnings(structs, groups["builder"])
+
+	if err != nil {
+		return err
+	}
+
+	names := make([]string, 0, len(groups))
+	for name := range groups {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	files := make([]*generatedFile, 0)
+
+	for _, name := range names {
+		results, err := generator.Generate(pkg, name, groups[name])
+
+		if err != nil {
+			return err
+		}
+
+		generated, err := collectFiles(pkg, results, name)
+
+		if err != nil {
+			return err
+		}
+
+		files = append(files, generated...)
+	}
+
+	return writeFiles(files)
+}
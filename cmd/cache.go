@@ -0,0 +1,226 @@
+/*
+Copyright © 2020 Singee <i@singee.me>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/ImSingee/god/generator"
+	"github.com/ImSingee/god/plugin"
+	"github.com/ImSingee/god/utils"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"go/build"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// excludedCacheSettings 是不影响生成结果的配置，不作为缓存的输入
+//
+// strict、no-typecheck 与 prune 虽然不影响生成的代码，但命中缓存时警告、类型检查与删除过期文件都会被跳过，因此作为缓存的输入
+var excludedCacheSettings = []string{
+	"cache-dir", "check", "debug", "diff", "dry-run", "jobs", "json", "no-cache", "output", "workdir",
+}
+
+// cacheSession 关联缓存记录与本次生成的文件，所有文件保存成功后更新缓存
+type cacheSession struct {
+	entry   *utils.CacheEntry
+	inputs  string
+	pending int
+	failed  bool
+}
+
+// currentCache 是当前正在执行的命令对应的缓存，由 useCache 设置，在 saveFiles 中与生成的文件关联
+var currentCache *cacheSession
+
+// 单文件输出时同一个包的所有指令需要一起生成，跳过其中一条指令会丢失它生成的代码，因此不使用缓存
+func cacheEnabled() bool {
+	return !viper.GetBool("single-file") && !viper.GetBool("no-cache") && !viper.GetBool("check") && !isDryRun() && viper.GetString("output") == ""
+}
+
+func cacheDir() (string, error) {
+	if dir := viper.GetString("cache-dir"); dir != "" {
+		return dir, nil
+	}
+
+	// 在 Linux 中为 $XDG_CACHE_HOME/god
+	dir, err := os.UserCacheDir()
+
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, "god"), nil
+}
+
+// executableStamp 用于在重新构建 god 之后使缓存失效
+func executableStamp() string {
+	executable, err := os.Executable()
+	if err != nil {
+		return ""
+	}
+
+	return fileStamp(executable)
+}
+
+func fileStamp(filename string) string {
+	info, err := os.Stat(filename)
+	if err != nil {
+		return filename
+	}
+
+	return fmt.Sprintf("%s %d %d", filename, info.Size(), info.ModTime().UnixNano())
+}
+
+// useCache 检查当前包的输入是否与上次生成时相同，相同并且输出文件没有被修改时返回 true
+func useCache(cmd *cobra.Command) (bool, error) {
+	currentCache = nil
+
+	if !cacheEnabled() {
+		return false, nil
+	}
+
+	dir, err := cacheDir()
+
+	if err != nil {
+		// 无法确定缓存目录时不使用缓存
+		return false, nil
+	}
+
+	wd, err := os.Getwd()
+
+	if err != nil {
+		return false, err
+	}
+
+	settings := viper.AllSettings()
+	for _, key := range excludedCacheSettings {
+		delete(settings, key)
+	}
+
+	settingsJSON, err := json.Marshal(settings)
+
+	if err != nil {
+		return false, fmt.Errorf("cannot encode settings: %w", err)
+	}
+
+	pkgInfo, err := build.ImportDir(".", 0)
+
+	if err != nil {
+		return false, fmt.Errorf("cannot build from package: %w", err)
+	}
+
+	extras := []string{executableStamp(), string(settingsJSON)}
+
+	// 自定义模板同样是输入
+	templates, err := generator.TemplateFiles(optionsFromViper())
+
+	if err != nil {
+		return false, err
+	}
+
+	for _, filename := range templates {
+		content, err := ioutil.ReadFile(filename)
+
+		if err != nil {
+			return false, fmt.Errorf("cannot read template %s: %w", filename, err)
+		}
+
+		extras = append(extras, filename, utils.HashBytes(content))
+	}
+
+	// 外部生成器更新后同样需要重新生成
+	plugins := plugin.List()
+	names := make([]string, 0, len(plugins))
+	for name := range plugins {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		extras = append(extras, fileStamp(plugins[name]))
+	}
+
+	inputs, err := utils.HashInputs(pkgInfo.GoFiles, extras...)
+
+	if err != nil {
+		return false, err
+	}
+
+	entry, err := utils.LoadCacheEntry(dir, wd+"\x00"+cmd.Name()+"\x00"+strings.Join(cmd.Flags().Args(), " ")+"\x00"+string(settingsJSON))
+
+	if err != nil {
+		return false, err
+	}
+
+	if entry.UpToDate(inputs) {
+		fmt.Printf("Skip %s, nothing changed\n", cmd.Name())
+		return true, nil
+	}
+
+	currentCache = &cacheSession{entry: entry, inputs: inputs}
+
+	return false, nil
+}
+
+// attach 将生成的文件与缓存关联，没有文件时直接更新缓存
+func (session *cacheSession) attach(files []*generatedFile) error {
+	session.entry.Outputs = make(map[string]string, len(files))
+	session.pending = len(files)
+
+	for _, file := range files {
+		filename, err := filepath.Abs(file.Filename)
+
+		if err != nil {
+			return err
+		}
+
+		file.cache = session
+		file.absFilename = filename
+	}
+
+	if session.pending == 0 {
+		session.save()
+	}
+
+	return nil
+}
+
+// done 记录一个文件的保存结果，所有文件都保存成功后更新缓存
+func (session *cacheSession) done(file *generatedFile, saved bool) {
+	if saved {
+		session.entry.Outputs[file.absFilename] = utils.HashBytes(file.Formatted)
+	} else {
+		session.failed = true
+	}
+
+	session.pending--
+
+	if session.pending == 0 && !session.failed {
+		session.save()
+	}
+}
+
+func (session *cacheSession) save() {
+	session.entry.Inputs = session.inputs
+
+	if err := session.entry.Save(); err != nil {
+		fmt.Fprintf(os.Stderr, "warning: cannot save cache: %s\n", err)
+	}
+}
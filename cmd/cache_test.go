@@ -0,0 +1,43 @@
+package cmd_test
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCache(t *testing.T) {
+	dir := newModule(t, map[string]string{"user.go": userSource})
+	filename := filepath.Join(dir, "user_getter.go")
+
+	god(t, dir, "getter", "-a").expect(t, 0, "save as user_getter.go")
+	god(t, dir, "getter", "-a").expect(t, 0, "Skip getter, nothing changed")
+
+	// 配置不同时不使用缓存
+	r := god(t, dir, "getter", "-a", "--strict")
+	r.expect(t, 0, "save as user_getter.go")
+
+	if strings.Contains(r.stdout, "Skip") {
+		t.Errorf("different settings should not hit the cache:\n%s", r.stdout)
+	}
+
+	god(t, dir, "getter", "-a", "--no-cache").expect(t, 0, "save as user_getter.go")
+
+	// 生成的文件被修改后重新生成
+	writeFile(t, filename, "package model\n")
+	god(t, dir, "getter", "-a").expect(t, 0, "save as user_getter.go")
+
+	if !strings.Contains(readFile(t, filename), "func (u *User) Name() string {") {
+		t.Error("modified generated file should be regenerated")
+	}
+
+	// 源码改变后重新生成
+	writeFile(t, filepath.Join(dir, "user.go"), strings.Replace(userSource, "Age  int", "Age  int\n\temail string", 1))
+	god(t, dir, "getter", "-a").expect(t, 0, "save as user_getter.go")
+
+	if !strings.Contains(readFile(t, filename), "func (u *User) Email() string {") {
+		t.Error("generated file should be updated after the struct changes")
+	}
+
+	god(t, dir, "getter", "-a").expect(t, 0, "Skip getter, nothing changed")
+}
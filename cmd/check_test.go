@@ -0,0 +1,31 @@
+package cmd_test
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCheck(t *testing.T) {
+	dir := newModule(t, map[string]string{"user.go": userSource})
+
+	// 文件缺失
+	r := god(t, dir, "getter", "-a", "--check")
+	r.expect(t, 1, "missing: user_getter.go")
+
+	if !strings.Contains(r.stderr, "1 generated file(s) are out of date") {
+		t.Errorf("unexpected stderr: %s", r.stderr)
+	}
+
+	if fileExists(filepath.Join(dir, "user_getter.go")) {
+		t.Error("--check should not write files")
+	}
+
+	god(t, dir, "getter", "-a").expect(t, 0, "save as user_getter.go")
+	god(t, dir, "getter", "-a", "--check").expect(t, 0)
+
+	// 结构体改变后文件过期
+	writeFile(t, filepath.Join(dir, "user.go"), strings.Replace(userSource, "Age  int", "Age  int\n\temail string", 1))
+
+	god(t, dir, "getter", "-a", "--check").expect(t, 1, "stale: user_getter.go")
+}
@@ -0,0 +1,76 @@
+/*
+Copyright © 2020 Singee <i@singee.me>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"fmt"
+	"github.com/ImSingee/god/generator"
+	"github.com/ImSingee/god/utils"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"os"
+)
+
+// cleanCmd represents the clean command
+var cleanCmd = &cobra.Command{
+	Use:   "clean",
+	Short: "Remove generated files whose source structs or generators no longer exist",
+	RunE:  runClean,
+}
+
+func init() {
+	rootCmd.AddCommand(cleanCmd)
+}
+
+func runClean(cmd *cobra.Command, args []string) error {
+	return pruneFiles()
+}
+
+// pruneFiles 删除当前目录中过期的生成文件，dry-run 模式下只列出，check 模式下存在过期文件时返回错误
+func pruneFiles() error {
+	options := optionsFromViper()
+
+	stale, err := utils.FindStaleGeneratedFiles(".", func(name string) bool {
+		return generator.Exists(options, name)
+	})
+
+	if err != nil {
+		return err
+	}
+
+	for _, file := range stale {
+		switch {
+		case viper.GetBool("check"):
+			fmt.Printf("orphaned: %s (%s)\n", file.Filename, file.Reason)
+		case isDryRun():
+			fmt.Printf("Would remove %s (%s)\n", file.Filename, file.Reason)
+		default:
+			err := os.Remove(file.Filename)
+
+			if err != nil {
+				return fmt.Errorf("cannot remove file %s: %w", file.Filename, err)
+			}
+
+			fmt.Printf("Remove %s (%s)\n", file.Filename, file.Reason)
+		}
+	}
+
+	if viper.GetBool("check") && len(stale) != 0 {
+		return fmt.Errorf("%d orphaned generated file(s) found", len(stale))
+	}
+
+	return nil
+}
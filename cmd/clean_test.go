@@ -0,0 +1,92 @@
+package cmd_test
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestClean(t *testing.T) {
+	dir := newModule(t, map[string]string{
+		"user.go":  userSource,
+		"order.go": "package model\n\ntype Order struct {\n\tid int\n}\n",
+	})
+
+	god(t, dir, "getter", "-a").expect(t, 0, "save as user_getter.go", "save as order_getter.go")
+	god(t, dir, "builder", "-a").expect(t, 0, "save as user_builder.go")
+	writeFile(t, filepath.Join(dir, "user.go"), "package model\n")
+
+	r := god(t, dir, "clean", "--dry-run")
+	r.expect(t, 0,
+		"Would remove user_builder.go (struct User no longer exists)",
+		"Would remove user_getter.go (struct User no longer exists)",
+	)
+
+	if !fileExists(filepath.Join(dir, "user_getter.go")) {
+		t.Fatal("clean --dry-run should not remove files")
+	}
+
+	r = god(t, dir, "clean")
+	r.expect(t, 0, "Remove user_builder.go", "Remove user_getter.go")
+
+	if strings.Contains(r.stdout, "order_") {
+		t.Errorf("files of existing structs should be kept:\n%s", r.stdout)
+	}
+
+	for _, name := range []string{"user_getter.go", "user_builder.go"} {
+		if fileExists(filepath.Join(dir, name)) {
+			t.Errorf("%s should be removed", name)
+		}
+	}
+
+	if !fileExists(filepath.Join(dir, "order_getter.go")) {
+		t.Error("order_getter.go should be kept")
+	}
+
+	god(t, dir, "clean", "--check").expect(t, 0)
+}
+
+func TestCheckOrphaned(t *testing.T) {
+	dir := newModule(t, map[string]string{"user.go": userSource})
+
+	god(t, dir, "getter", "-a").expect(t, 0)
+	writeFile(t, filepath.Join(dir, "user.go"), "package model\n")
+
+	r := god(t, dir, "clean", "--check")
+	r.expect(t, 1, "orphaned: user_getter.go (struct User no longer exists)")
+
+	if !strings.Contains(r.stderr, "1 orphaned generated file(s) found") {
+		t.Errorf("unexpected stderr: %s", r.stderr)
+	}
+
+	if !fileExists(filepath.Join(dir, "user_getter.go")) {
+		t.Error("clean --check should not remove files")
+	}
+}
+
+func TestPrune(t *testing.T) {
+	dir := newModule(t, map[string]string{
+		"user.go":  userSource,
+		"order.go": "package model\n\ntype Order struct {\n\tid int\n}\n",
+	})
+
+	god(t, dir, "getter", "-a").expect(t, 0)
+	writeFile(t, filepath.Join(dir, "order.go"), "package model\n")
+
+	// 不指定 --prune 时保留过期的文件
+	god(t, dir, "getter", "-a").expect(t, 0)
+
+	if !fileExists(filepath.Join(dir, "order_getter.go")) {
+		t.Fatal("order_getter.go should be kept without --prune")
+	}
+
+	god(t, dir, "getter", "-a", "--prune").expect(t, 0, "Remove order_getter.go (struct Order no longer exists)")
+
+	if fileExists(filepath.Join(dir, "order_getter.go")) {
+		t.Error("order_getter.go should be pruned")
+	}
+
+	if !fileExists(filepath.Join(dir, "user_getter.go")) {
+		t.Error("user_getter.go should be kept")
+	}
+}
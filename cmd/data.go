@@ -16,82 +16,31 @@ limitations under the License.
 package cmd
 
 import (
-	"fmt"
 	"github.com/ImSingee/god/generator"
-	"github.com/ImSingee/god/utils"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
 
-// getterCmd represents the getter command
+// dataCmd represents the data command
 var dataCmd = &cobra.Command{
 	Use:   "data",
-	Short: "Generate getter and setter functions for specific struct",
-	RunE:  runData,
+	Short: "Generate getter and setter (or generators chosen by --gen) functions for specific struct",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runGenerators(cmd, viper.GetStringSlice("generators"))
+	},
 }
 
 func init() {
 	rootCmd.AddCommand(dataCmd)
 
 	dataCmd.Flags().StringSliceP("struct", "t", []string{}, "Name list for structs")
+	dataCmd.Flags().StringSliceP("gen", "", []string{"getter", "setter"}, "generators to run, e.g. getter,setter,builder")
 
-	_ = viper.BindPFlags(dataCmd.Flags())
-}
-
-func runData(cmd *cobra.Command, args []string) error {
-	structs, err := utils.GetStructsFromPackage()
-
-	if err != nil {
-		return err
-	}
-
-	t := utils.GetTemplate("filename", viper.GetString("filename"))
-
-	// Generate Getters
-
-	results, err := generator.GenerateGetters(structs)
-
-	if err != nil {
-		return err
-	}
-
-	for s, result := range results {
-		filename := utils.ExecuteTemplate(t, map[string]interface{}{
-			"struct": s,
-			"type":   "getter",
-		})
-
-		err := utils.SaveGoCodeToFile(filename, result)
-
-		if err != nil {
-			return fmt.Errorf("cannot save to file %s: %w", filename, err)
-		}
-
-		fmt.Printf("Generate getter for struct %s, save as %s\n", s.Name, filename)
-	}
-
-	// Generate Setters
-
-	results, err = generator.GenerateSetters(structs)
-
-	if err != nil {
-		return err
+	// 所有生成器的参数都可以在 data 中使用
+	for _, g := range generator.Registered() {
+		g.Flags(dataCmd.Flags())
 	}
 
-	for s, result := range results {
-		filename := utils.ExecuteTemplate(t, map[string]interface{}{
-			"struct": s,
-			"type":   "setter",
-		})
-
-		err := utils.SaveGoCodeToFile(filename, result)
-
-		if err != nil {
-			return fmt.Errorf("cannot save to file %s: %w", filename, err)
-		}
-
-		fmt.Printf("Generate setter for struct %s, save as %s\n", s.Name, filename)
-	}
-
-	return nil
+	_ = viper.BindPFlags(dataCmd.Flags())
+	_ = viper.BindPFlag("generators", dataCmd.Flags().Lookup("gen"))
 }
@@ -0,0 +1,151 @@
+/*
+Copyright © 2020 Singee <i@singee.me>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/ImSingee/god/utils"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"os"
+	"text/tabwriter"
+)
+
+// explainCmd represents the explain command
+var explainCmd = &cobra.Command{
+	Use:   "explain",
+	Short: "Show whether each generator will emit a method for every field, and why not",
+	RunE:  runExplain,
+	// 输出可能是 JSON，不输出 Done!
+	PersistentPostRun: func(cmd *cobra.Command, args []string) {},
+}
+
+func init() {
+	rootCmd.AddCommand(explainCmd)
+
+	explainCmd.Flags().StringSliceP("struct", "t", []string{}, "Name list for structs")
+	explainCmd.Flags().BoolP("json", "", false, "output as JSON")
+
+	_ = viper.BindPFlags(explainCmd.Flags())
+}
+
+type methodDecision struct {
+	Name     string `json:"name,omitempty"`
+	Generate bool   `json:"generate"`
+	Reason   string `json:"reason,omitempty"`
+	Message  string `json:"message,omitempty"`
+}
+
+type fieldDecision struct {
+	Name     string         `json:"name"`
+	Type     string         `json:"type"`
+	Position string         `json:"position"`
+	Getter   methodDecision `json:"getter"`
+	Setter   methodDecision `json:"setter"`
+	Builder  methodDecision `json:"builder"`
+}
+
+type structDecision struct {
+	Name     string          `json:"name"`
+	Position string          `json:"position"`
+	Fields   []fieldDecision `json:"fields"`
+}
+
+func newMethodDecision(name string, generate bool, skipped *utils.Diagnostic) methodDecision {
+	decision := methodDecision{
+		Name:     name,
+		Generate: generate,
+	}
+
+	if !generate && skipped != nil {
+		decision.Reason = string(skipped.Reason)
+		decision.Message = skipped.Message
+	}
+
+	return decision
+}
+
+func explainStructs(structs utils.Structs) []structDecision {
+	decisions := make([]structDecision, 0, len(structs))
+
+	for _, s := range structs.List() {
+		decision := structDecision{
+			Name:     s.Name,
+			Position: s.Pos.String(),
+			Fields:   make([]fieldDecision, 0, len(s.Fields)),
+		}
+
+		for _, field := range s.FieldList {
+			decision.Fields = append(decision.Fields, fieldDecision{
+				Name:     field.Name,
+				Type:     field.Type,
+				Position: field.Pos.String(),
+				Getter:   newMethodDecision(field.GetterName, field.WillGenerateGetter, field.GetterSkipped),
+				Setter:   newMethodDecision(field.SetterName, field.WillGenerateSetter, field.SetterSkipped),
+				Builder:  newMethodDecision(field.BuilderName, field.WillGenerateBuilder, field.BuilderSkipped),
+			})
+		}
+
+		decisions = append(decisions, decision)
+	}
+
+	return decisions
+}
+
+func (d methodDecision) String() string {
+	if d.Generate {
+		return d.Name
+	}
+
+	if d.Reason == "" {
+		return "-"
+	}
+
+	return fmt.Sprintf("- (%s: %s)", d.Reason, d.Message)
+}
+
+func runExplain(cmd *cobra.Command, args []string) error {
+	pkg, err := loadPackage(optionsFromViper())
+
+	if err != nil {
+		return err
+	}
+
+	decisions := explainStructs(pkg.Structs)
+
+	if viper.GetBool("json") {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+
+		return encoder.Encode(decisions)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+
+	for _, s := range decisions {
+		fmt.Fprintf(w, "%s (%s)\n", s.Name, s.Position)
+		fmt.Fprintf(w, "  FIELD\tTYPE\tGETTER\tSETTER\tBUILDER\n")
+
+		for _, field := range s.Fields {
+			fmt.Fprintf(w, "  %s\t%s\t%s\t%s\t%s\n", field.Name, field.Type, field.Getter, field.Setter, field.Builder)
+		}
+
+		fmt.Fprintln(w)
+	}
+
+	return w.Flush()
+}
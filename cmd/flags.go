@@ -0,0 +1,77 @@
+/*
+Copyright © 2020 Singee <i@singee.me>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"github.com/spf13/pflag"
+	"strings"
+)
+
+// flagState 记录某个 flag 的值以及是否被显式设置
+type flagState struct {
+	value   string
+	changed bool
+}
+
+// snapshotFlags 记录 flag 集合当前的状态
+func snapshotFlags(fs *pflag.FlagSet) map[string]flagState {
+	states := make(map[string]flagState)
+
+	fs.VisitAll(func(f *pflag.Flag) {
+		states[f.Name] = flagState{value: f.Value.String(), changed: f.Changed}
+	})
+
+	return states
+}
+
+// resetFlags 将 flag 恢复为 states 中记录的状态，不在 states 中的恢复为默认值
+func resetFlags(fs *pflag.FlagSet, states map[string]flagState) error {
+	var err error
+
+	fs.VisitAll(func(f *pflag.Flag) {
+		if err != nil {
+			return
+		}
+
+		state, ok := states[f.Name]
+		if !ok {
+			state = flagState{value: f.DefValue}
+		}
+
+		err = setFlagValue(f, state.value)
+		f.Changed = state.changed
+	})
+
+	return err
+}
+
+// setFlagValue 覆盖 flag 的值；slice 类型的 Set 会追加而不是覆盖，因此需要先替换为新的 Value
+func setFlagValue(f *pflag.Flag, value string) error {
+	if f.Value.Type() != "stringSlice" {
+		return f.Value.Set(value)
+	}
+
+	tmp := pflag.NewFlagSet(f.Name, pflag.ContinueOnError)
+	tmp.StringSlice(f.Name, nil, f.Usage)
+	f.Value = tmp.Lookup(f.Name).Value
+
+	value = strings.TrimSuffix(strings.TrimPrefix(value, "["), "]")
+	if value == "" {
+		return nil
+	}
+
+	return f.Value.Set(value)
+}
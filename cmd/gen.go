@@ -0,0 +1,62 @@
+/*
+Copyright © 2020 Singee <i@singee.me>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"fmt"
+	"github.com/ImSingee/god/generator"
+	"github.com/ImSingee/god/plugin"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// genCmd represents the gen command
+var genCmd = &cobra.Command{
+	Use:   "gen <name>",
+	Short: "Generate code for specific struct with a custom template or an external generator",
+	Long: `Gen executes <name>.tmpl in the templates directory (the templates config)
+for every struct, with the same pkg/struct/header data as the built-in templates.
+
+Templates named getter.tmpl, setter.tmpl or builder.tmpl replace the built-in ones.
+
+Without a template, an executable named god-gen-<name> in PATH is used, see
+package plugin for the protocol.`,
+	RunE: runGen,
+}
+
+func init() {
+	rootCmd.AddCommand(genCmd)
+
+	genCmd.Flags().StringSliceP("struct", "t", []string{}, "Name list for structs")
+
+	_ = viper.BindPFlags(genCmd.Flags())
+}
+
+func runGen(cmd *cobra.Command, args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("gen requires exactly one generator name")
+	}
+
+	name := args[0]
+
+	options := optionsFromViper()
+
+	if !generator.Exists(options, name) {
+		return fmt.Errorf("unknown generator %s, no %s%s in templates directory or %s%s in PATH", name, name, generator.TEMPLATE_EXT, plugin.EXECUTABLE_PREFIX, name)
+	}
+
+	return runGenerators(cmd, []string{name})
+}
@@ -0,0 +1,104 @@
+/*
+Copyright © 2020 Singee <i@singee.me>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"fmt"
+	"github.com/ImSingee/god/generator"
+	"github.com/ImSingee/god/utils"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+func init() {
+	for _, g := range generator.Registered() {
+		rootCmd.AddCommand(generatorCommand(g))
+	}
+}
+
+// generatorCommand 为已注册的生成器创建同名的命令
+func generatorCommand(g generator.Generator) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   g.Name(),
+		Short: fmt.Sprintf("Generate %s functions for specific struct", g.Name()),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runGenerators(cmd, []string{g.Name()})
+		},
+	}
+
+	cmd.Flags().StringSliceP("struct", "t", []string{}, "Name list for structs")
+	g.Flags(cmd.Flags())
+
+	_ = viper.BindPFlags(cmd.Flags())
+
+	return cmd
+}
+
+// runGenerators 使用生成器为当前包中的结构体生成代码
+func runGenerators(cmd *cobra.Command, names []string) error {
+	options := optionsFromViper()
+
+	for _, name := range names {
+		if _, err := generator.Lookup(options, name); err != nil {
+			return err
+		}
+	}
+
+	upToDate, err := useCache(cmd)
+
+	if err != nil || upToDate {
+		return err
+	}
+
+	pkg, err := loadPackage(options)
+
+	if err != nil {
+		return err
+	}
+
+	var builders utils.Structs
+	for _, name := range names {
+		if name == "builder" {
+			builders = pkg.Structs
+		}
+	}
+
+	err = reportWarnings(pkg.Structs, builders)
+
+	if err != nil {
+		return err
+	}
+
+	files := make([]*generatedFile, 0)
+
+	for _, name := range names {
+		results, err := generator.Generate(pkg, name, pkg.Structs)
+
+		if err != nil {
+			return err
+		}
+
+		generated, err := collectFiles(pkg, results, name)
+
+		if err != nil {
+			return err
+		}
+
+		files = append(files, generated...)
+	}
+
+	return writeFiles(files)
+}
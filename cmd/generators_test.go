@@ -0,0 +1,43 @@
+package cmd_test
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGeneratorCommands(t *testing.T) {
+	dir := newModule(t, map[string]string{"user.go": userSource})
+
+	for name, method := range map[string]string{
+		"getter":  "func (u *User) Name() string {",
+		"setter":  "func (u *User) SetName(name string) {",
+		"builder": "func (b *UserBuilder) WithName(name string) *UserBuilder {",
+	} {
+		god(t, dir, name, "-a").expect(t, 0, "save as user_"+name+".go")
+
+		if content := readFile(t, filepath.Join(dir, "user_"+name+".go")); !strings.Contains(content, method) {
+			t.Errorf("user_%s.go does not contain %q:\n%s", name, method, content)
+		}
+	}
+}
+
+func TestGenTemplate(t *testing.T) {
+	dir := newModule(t, map[string]string{
+		"user.go":               userSource,
+		"templates/fields.tmpl": "package {{ $.pkg }}\n\n// {{ $.struct.Name }}Fields 是 {{ $.struct.Name }} 的字段数量\nconst {{ $.struct.Name }}Fields = {{ len $.struct.FieldList }}\n",
+	})
+
+	god(t, dir, "gen", "fields", "-a", "--templates", "templates").expect(t, 0, "save as user_fields.go")
+
+	if content := readFile(t, filepath.Join(dir, "user_fields.go")); !strings.Contains(content, "const UserFields = 2") {
+		t.Errorf("unexpected user_fields.go:\n%s", content)
+	}
+
+	r := god(t, dir, "gen", "unknown", "-a", "--templates", "templates")
+	r.expect(t, 1)
+
+	if !strings.Contains(r.stderr, "unknown generator unknown") {
+		t.Errorf("unexpected stderr: %s", r.stderr)
+	}
+}
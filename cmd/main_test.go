@@ -0,0 +1,135 @@
+package cmd_test
+
+import (
+	"bytes"
+	"github.com/ImSingee/god/cmd"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// GOD_MODE_ENV 不为空时测试程序本身作为 god 命令运行
+const GOD_MODE_ENV = "GOD_CMD_TEST_MODE"
+
+func TestMain(m *testing.M) {
+	if os.Getenv(GOD_MODE_ENV) == "" {
+		os.Exit(m.Run())
+	}
+
+	cmd.Execute()
+	os.Exit(0)
+}
+
+const userSource = `package model
+
+type User struct {
+	name string
+	Age  int
+}
+`
+
+// result 是 god 命令的执行结果
+type result struct {
+	code   int
+	stdout string
+	stderr string
+}
+
+// newModule 在临时目录中创建包含 files 的模块，返回模块所在的目录
+func newModule(t *testing.T, files map[string]string) string {
+	t.Helper()
+
+	dir := filepath.Join(t.TempDir(), "module")
+	files["go.mod"] = "module example.com/model\n\ngo 1.16\n"
+
+	for name, content := range files {
+		writeFile(t, filepath.Join(dir, name), content)
+	}
+
+	return dir
+}
+
+func writeFile(t *testing.T, filename, content string) {
+	t.Helper()
+
+	if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := ioutil.WriteFile(filename, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func readFile(t *testing.T, filename string) string {
+	t.Helper()
+
+	b, err := ioutil.ReadFile(filename)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return string(b)
+}
+
+func fileExists(filename string) bool {
+	_, err := os.Stat(filename)
+	return err == nil
+}
+
+// godCommand 返回在 dir 中执行 god args 的命令，缓存目录与模块位于同一个临时目录中
+func godCommand(t *testing.T, dir string, args ...string) *exec.Cmd {
+	t.Helper()
+
+	c := exec.Command(os.Args[0], args...)
+	c.Dir = dir
+	c.Env = append(os.Environ(),
+		GOD_MODE_ENV+"=1",
+		"GOPACKAGE=model",
+		"GOFILE=",
+		"XDG_CACHE_HOME="+filepath.Join(filepath.Dir(dir), "cache"),
+	)
+
+	return c
+}
+
+// god 在 dir 中执行 god args 并等待结束
+func god(t *testing.T, dir string, args ...string) *result {
+	t.Helper()
+
+	c := godCommand(t, dir, args...)
+
+	stdout, stderr := bytes.Buffer{}, bytes.Buffer{}
+	c.Stdout, c.Stderr = &stdout, &stderr
+
+	err := c.Run()
+
+	if _, ok := err.(*exec.ExitError); err != nil && !ok {
+		t.Fatal(err)
+	}
+
+	return &result{
+		code:   c.ProcessState.ExitCode(),
+		stdout: stdout.String(),
+		stderr: stderr.String(),
+	}
+}
+
+// expect 检查退出码，并且 stdout 包含 contains 中的所有内容
+func (r *result) expect(t *testing.T, code int, contains ...string) {
+	t.Helper()
+
+	if r.code != code {
+		t.Fatalf("exit code = %d, want %d\nstdout:\n%s\nstderr:\n%s", r.code, code, r.stdout, r.stderr)
+	}
+
+	for _, s := range contains {
+		if !strings.Contains(r.stdout, s) {
+			t.Errorf("stdout does not contain %q:\n%s", s, r.stdout)
+		}
+	}
+}
@@ -0,0 +1,57 @@
+/*
+Copyright © 2020 Singee <i@singee.me>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"fmt"
+	"github.com/ImSingee/god/utils"
+	"github.com/spf13/viper"
+)
+
+// optionsFromViper 使用命令行参数与配置文件中的配置构造选项
+func optionsFromViper() *utils.Options {
+	return &utils.Options{
+		Package:     viper.GetString("gopackage"),
+		File:        viper.GetString("gofile"),
+		Structs:     viper.GetStringSlice("struct"),
+		All:         viper.GetBool("all"),
+		Include:     viper.GetStringSlice("include"),
+		Exclude:     viper.GetStringSlice("exclude"),
+		Naming:      viper.GetString("naming"),
+		BoolPrefix:  viper.GetString("bool-prefix"),
+		GetterName:  viper.GetString("getter-name"),
+		SetterName:  viper.GetString("setter-name"),
+		Initialisms: viper.GetStringSlice("initialisms"),
+		Sort:        viper.GetString("sort"),
+		Filename:    viper.GetString("filename"),
+		Header:      viper.GetString("header"),
+		Templates:   viper.GetString("templates"),
+		Jobs:        viper.GetInt("jobs"),
+		NoTypeCheck: viper.GetBool("no-typecheck"),
+		Settings:    viper.AllSettings(),
+
+		BuilderPrefix: viper.GetString("builder-prefix"),
+	}
+}
+
+// loadPackage 加载当前目录中的包
+func loadPackage(options *utils.Options) (*utils.Package, error) {
+	if options.Package == "" {
+		return nil, fmt.Errorf("missing package name (gopackage config)")
+	}
+
+	return utils.LoadPackage(".", options)
+}
@@ -0,0 +1,519 @@
+/*
+Copyright © 2020 Singee <i@singee.me>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/ImSingee/god/utils"
+	"github.com/spf13/viper"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// generatedFile 是一个生成的文件
+type generatedFile struct {
+	Struct    *utils.Struct // 合并后的文件为 nil
+	Type      string        // 生成器类型，例如 getter
+	Filename  string
+	Content   []byte // 未格式化的代码
+	Formatted []byte // 格式化后的代码，由 formatFiles 填充
+
+	singleFilename string   // 不为空时与同一个包中的其他文件合并到这个文件中（--single-file）
+	replaces       []string // 合并后的文件替代的、之前为每个结构体生成的文件
+
+	cache       *cacheSession // 文件所属的缓存，为 nil 时不使用缓存
+	absFilename string
+}
+
+// pendingOutput 不为 nil 时（run 命令）生成的文件不会立即保存，而是在所有指令执行完后统一并发地格式化与保存
+var pendingOutput *pendingFiles
+
+type pendingFiles struct {
+	wd    string // 文件名相对的目录
+	files []*generatedFile
+}
+
+// rel 将当前目录中的文件名转换为相对于 wd 的路径
+func (p *pendingFiles) rel(filename string) (string, error) {
+	wd, err := os.Getwd()
+
+	if err != nil {
+		return "", err
+	}
+
+	if !filepath.IsAbs(filename) {
+		filename = filepath.Join(wd, filename)
+	}
+
+	return filepath.Rel(p.wd, filename)
+}
+
+// add 将文件名转换为相对于 wd 的路径后加入等待保存的列表
+func (p *pendingFiles) add(files []*generatedFile) error {
+	for _, file := range files {
+		filename, err := p.rel(file.Filename)
+
+		if err != nil {
+			return err
+		}
+
+		file.Filename = filename
+		p.files = append(p.files, file)
+	}
+
+	return nil
+}
+
+// displayPath 返回用于输出的文件名，run 命令中为相对于执行目录的路径
+func displayPath(filename string) string {
+	if pendingOutput == nil || filename == "" {
+		return filename
+	}
+
+	if rel, err := pendingOutput.rel(filename); err == nil {
+		return rel
+	}
+
+	return filename
+}
+
+func (file *generatedFile) String() string {
+	if file.Struct == nil {
+		return file.Type
+	}
+
+	return fmt.Sprintf("%s for struct %s", file.Type, file.Struct.Name)
+}
+
+// collectFiles 根据 filename 模板为每个结构体的生成结果确定文件名
+func collectFiles(pkg *utils.Package, results map[*utils.Struct][]byte, typ string) ([]*generatedFile, error) {
+	t, err := utils.GetTemplate("filename", pkg.Options.FilenameTemplate(), pkg.Options)
+
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]*utils.Struct, 0, len(results))
+	for s := range results {
+		list = append(list, s)
+	}
+	utils.SortStructs(list)
+
+	files := make([]*generatedFile, 0, len(results))
+
+	for _, s := range list {
+		result := results[s]
+		filename, err := utils.ExecuteTemplate(t, map[string]interface{}{
+			"struct": s,
+			"type":   typ,
+		})
+
+		if err != nil {
+			return nil, err
+		}
+
+		files = append(files, &generatedFile{
+			Struct:   s,
+			Type:     typ,
+			Filename: filename,
+			Content:  result,
+		})
+	}
+
+	return files, nil
+}
+
+// isDryRun 表示只输出 diff 而不写入文件
+func isDryRun() bool {
+	return viper.GetBool("dry-run") || viper.GetBool("diff")
+}
+
+// isStdout 表示生成的代码输出到 stdout 而不写入文件
+func isStdout() bool {
+	return viper.GetString("output") == "-"
+}
+
+// isZip 表示生成的代码写入 output 指定的 zip 压缩包而不写入文件
+func isZip() bool {
+	return strings.HasSuffix(viper.GetString("output"), ".zip")
+}
+
+// writeFiles 格式化并保存生成的文件，指定了 prune 时会删除过期的生成文件
+func writeFiles(files []*generatedFile) error {
+	err := saveFiles(files)
+
+	if err != nil {
+		return err
+	}
+
+	// 输出到 stdout 或 zip 时磁盘上的文件不会被更新，也不应该被删除
+	if viper.GetBool("prune") && viper.GetString("output") == "" {
+		return pruneFiles()
+	}
+
+	return nil
+}
+
+// saveFiles 格式化并保存生成的文件，check 模式下只比较而不写入，dry-run 模式下只输出 diff
+func saveFiles(files []*generatedFile) error {
+	output := viper.GetString("output")
+	if output != "" && !isStdout() && !isZip() {
+		return fmt.Errorf("unsupported output %s, only - (stdout) and *.zip are supported", output)
+	}
+
+	// 合并在 emitFiles 中按照包进行，run 命令中同一个包的多条指令生成的代码会合并到同一个文件中
+	if viper.GetBool("single-file") {
+		for _, file := range files {
+			file.singleFilename = viper.GetString("single-filename")
+		}
+	}
+
+	if currentCache != nil {
+		err := currentCache.attach(files)
+		currentCache = nil
+
+		if err != nil {
+			return err
+		}
+	}
+
+	if pendingOutput != nil {
+		return pendingOutput.add(files)
+	}
+
+	return emitFiles(files)
+}
+
+// emitFiles 并发地格式化所有文件，然后根据模式保存、比较或输出
+func emitFiles(files []*generatedFile) error {
+	files, err := combineFiles(files)
+
+	if err != nil {
+		return err
+	}
+
+	err = checkFilenames(files)
+
+	if err != nil {
+		return err
+	}
+
+	err = formatFiles(files)
+
+	if err != nil {
+		return err
+	}
+
+	err = findReplacedFiles(files)
+
+	if err != nil {
+		return err
+	}
+
+	if !viper.GetBool("no-typecheck") {
+		err = typeCheckFiles(files)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	switch {
+	case viper.GetBool("check"):
+		return checkFiles(files)
+	case isDryRun():
+		return diffFiles(files)
+	case isStdout():
+		return printFiles(files)
+	case isZip():
+		return zipFiles(files)
+	}
+
+	saved := make([]bool, len(files))
+
+	err = utils.RunParallel(optionsFromViper().Workers(), len(files), func(i int) error {
+		if viper.GetBool("debug") {
+			fmt.Printf("Save %s:\n%s", files[i].Filename, files[i].Formatted)
+		}
+
+		err := utils.DiskWriter{}.WriteFile(files[i].Filename, files[i].Formatted)
+
+		if err != nil {
+			return fmt.Errorf("cannot save to file %s: %w", files[i].Filename, err)
+		}
+
+		saved[i] = true
+
+		return nil
+	})
+
+	for i, file := range files {
+		if saved[i] {
+			fmt.Printf("Generate %s, save as %s\n", file, file.Filename)
+
+			if e := removeReplacedFiles(file); e != nil && err == nil {
+				err = e
+			}
+		}
+
+		if file.cache != nil {
+			file.cache.done(file, saved[i])
+		}
+	}
+
+	return err
+}
+
+// removeReplacedFiles 删除合并后的文件替代的文件
+func removeReplacedFiles(file *generatedFile) error {
+	for _, filename := range file.replaces {
+		err := os.Remove(filename)
+
+		if err != nil {
+			return fmt.Errorf("cannot remove file %s: %w", filename, err)
+		}
+
+		fmt.Printf("Remove %s (replaced by %s)\n", filename, file.Filename)
+	}
+
+	return nil
+}
+
+// formatFiles 使用 jobs 个 goroutine 并发地格式化所有文件，返回所有文件的错误
+func formatFiles(files []*generatedFile) error {
+	return utils.RunParallel(optionsFromViper().Workers(), len(files), func(i int) error {
+		content, err := utils.FormatGoCode(files[i].Filename, files[i].Content)
+
+		if err != nil {
+			return fmt.Errorf("cannot format generated code for %s: %w", files[i].Filename, err)
+		}
+
+		files[i].Formatted = content
+
+		return nil
+	})
+}
+
+// combineFiles 将每个包中指定了 single-filename 的文件合并到一个文件中，其他文件保持不变
+func combineFiles(files []*generatedFile) ([]*generatedFile, error) {
+	results := make([]*generatedFile, 0, len(files))
+	groups := make(map[string][]*generatedFile)
+	keys := make([]string, 0)
+
+	for _, file := range files {
+		if file.singleFilename == "" {
+			results = append(results, file)
+			continue
+		}
+
+		filename := filepath.Join(filepath.Dir(file.Filename), file.singleFilename)
+		if _, ok := groups[filename]; !ok {
+			keys = append(keys, filename)
+		}
+		groups[filename] = append(groups[filename], file)
+	}
+
+	for _, filename := range keys {
+		file, err := combinePackageFiles(filename, groups[filename])
+
+		if err != nil {
+			return nil, err
+		}
+
+		results = append(results, file)
+	}
+
+	return results, nil
+}
+
+// combinePackageFiles 将一个包中生成的代码合并到 filename 中
+func combinePackageFiles(filename string, files []*generatedFile) (*generatedFile, error) {
+	contents := make([][]byte, 0, len(files))
+	types := make([]string, 0)
+	// run 命令中每条指令分别加载包，同一个结构体对应不同的 *utils.Struct
+	structs := make(map[string]bool)
+
+	for _, file := range files {
+		contents = append(contents, file.Content)
+
+		if len(types) == 0 || types[len(types)-1] != file.Type {
+			types = append(types, file.Type)
+		}
+		structs[file.Struct.Name] = true
+	}
+
+	content, err := utils.MergeGoCode(filename, contents, optionsFromViper())
+
+	if err != nil {
+		return nil, fmt.Errorf("cannot merge generated code into %s: %w", filename, err)
+	}
+
+	return &generatedFile{
+		Type:           fmt.Sprintf("%s for %d struct(s)", strings.Join(types, ", "), len(structs)),
+		Filename:       filename,
+		Content:        content,
+		singleFilename: filepath.Base(filename),
+	}, nil
+}
+
+// checkFilenames 检查不同的生成结果不会写入同一个文件
+func checkFilenames(files []*generatedFile) error {
+	seen := make(map[string]*generatedFile, len(files))
+
+	for _, file := range files {
+		filename := filepath.Clean(file.Filename)
+
+		if other, ok := seen[filename]; ok {
+			return fmt.Errorf("%s and %s would both be saved as %s, check the filename config", other, file, filename)
+		}
+
+		seen[filename] = file
+	}
+
+	return nil
+}
+
+// findReplacedFiles 找到合并后的文件替代的、之前为每个结构体生成的文件，这些文件会在合并后的文件保存后被删除
+func findReplacedFiles(files []*generatedFile) error {
+	for _, file := range files {
+		if file.singleFilename == "" {
+			continue
+		}
+
+		replaced, err := utils.FindReplacedGeneratedFiles(filepath.Dir(file.Filename), file.Filename, file.Formatted)
+
+		if err != nil {
+			return err
+		}
+
+		file.replaces = replaced
+	}
+
+	return nil
+}
+
+// checkFiles 检查磁盘上的文件是否与生成的内容一致，列出过期或缺失的文件
+func checkFiles(files []*generatedFile) error {
+	outdated := 0
+
+	for _, file := range files {
+		content := file.Formatted
+		existed, err := ioutil.ReadFile(file.Filename)
+
+		switch {
+		case os.IsNotExist(err):
+			fmt.Printf("missing: %s (%s)\n", file.Filename, file)
+			outdated++
+		case err != nil:
+			return fmt.Errorf("cannot read file %s: %w", file.Filename, err)
+		case !bytes.Equal(existed, content):
+			fmt.Printf("stale: %s (%s)\n", file.Filename, file)
+			outdated++
+		}
+
+		for _, filename := range file.replaces {
+			fmt.Printf("obsolete: %s (replaced by %s)\n", filename, file.Filename)
+			outdated++
+		}
+	}
+
+	if outdated != 0 {
+		return fmt.Errorf("%d generated file(s) are out of date", outdated)
+	}
+
+	return nil
+}
+
+// diffFiles 以 unified diff 的格式输出生成的内容与磁盘上的文件之间的差异
+func diffFiles(files []*generatedFile) error {
+	for _, file := range files {
+		fromName := "a/" + file.Filename
+		existed, err := ioutil.ReadFile(file.Filename)
+
+		if os.IsNotExist(err) {
+			fromName = "/dev/null"
+		} else if err != nil {
+			return fmt.Errorf("cannot read file %s: %w", file.Filename, err)
+		}
+
+		fmt.Print(utils.UnifiedDiff(fromName, "b/"+file.Filename, existed, file.Formatted))
+
+		for _, filename := range file.replaces {
+			content, err := ioutil.ReadFile(filename)
+
+			if err != nil {
+				return fmt.Errorf("cannot read file %s: %w", filename, err)
+			}
+
+			fmt.Print(utils.UnifiedDiff("a/"+filename, "/dev/null", content, nil))
+		}
+	}
+
+	return nil
+}
+
+// printFiles 将格式化后的代码输出到 stdout，多个文件时在每个文件前输出文件名
+func printFiles(files []*generatedFile) error {
+	w := utils.NewStreamWriter(os.Stdout)
+	w.Header = len(files) > 1
+
+	return writeTo(w, files)
+}
+
+// zipFiles 将格式化后的代码写入 output 指定的 zip 压缩包，文件名为相对于当前目录的路径
+func zipFiles(files []*generatedFile) error {
+	output := viper.GetString("output")
+	f, err := os.Create(output)
+
+	if err != nil {
+		return fmt.Errorf("cannot create file %s: %w", output, err)
+	}
+	defer f.Close()
+
+	w := utils.NewZipWriter(f)
+
+	err = writeTo(w, files)
+
+	if err != nil {
+		return err
+	}
+
+	err = w.Close()
+
+	if err != nil {
+		return fmt.Errorf("cannot write file %s: %w", output, err)
+	}
+
+	fmt.Printf("Save %d file(s) to %s\n", len(files), output)
+
+	return nil
+}
+
+// writeTo 按顺序将格式化后的代码写入 w
+func writeTo(w utils.Writer, files []*generatedFile) error {
+	for _, file := range files {
+		err := w.WriteFile(file.Filename, file.Formatted)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,49 @@
+package cmd_test
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDryRun(t *testing.T) {
+	dir := newModule(t, map[string]string{"user.go": userSource})
+
+	for _, flag := range []string{"--dry-run", "--diff"} {
+		r := god(t, dir, "getter", "-a", flag)
+		r.expect(t, 0, "--- /dev/null", "+++ b/user_getter.go", "+func (u *User) Name() string {")
+
+		if fileExists(filepath.Join(dir, "user_getter.go")) {
+			t.Fatalf("%s should not write files", flag)
+		}
+	}
+
+	god(t, dir, "getter", "-a").expect(t, 0)
+	writeFile(t, filepath.Join(dir, "user.go"), strings.Replace(userSource, "Age  int", "Age  int\n\temail string", 1))
+
+	r := god(t, dir, "getter", "-a", "--dry-run")
+	r.expect(t, 0, "--- a/user_getter.go", "+++ b/user_getter.go", "+func (u *User) Email() string {")
+
+	if strings.Contains(r.stdout, "-func (u *User) Name() string {") {
+		t.Errorf("unchanged function should not be in the diff:\n%s", r.stdout)
+	}
+
+	if strings.Contains(readFile(t, filepath.Join(dir, "user_getter.go")), "Email()") {
+		t.Error("--dry-run should not update files")
+	}
+}
+
+func TestOutputStdout(t *testing.T) {
+	dir := newModule(t, map[string]string{"user.go": userSource})
+
+	r := god(t, dir, "getter", "-a", "-o", "-")
+	r.expect(t, 0, "package model", "func (u *User) Name() string {")
+
+	if strings.Contains(r.stdout, "---") {
+		t.Errorf("single file should be printed without a header:\n%s", r.stdout)
+	}
+
+	if fileExists(filepath.Join(dir, "user_getter.go")) {
+		t.Error("-o - should not write files")
+	}
+}
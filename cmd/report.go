@@ -0,0 +1,43 @@
+/*
+Copyright © 2020 Singee <i@singee.me>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"fmt"
+	"github.com/ImSingee/god/utils"
+	"github.com/spf13/viper"
+	"os"
+)
+
+// reportWarnings 以 file:line:col 的格式输出警告，builders 是需要生成 Builder 的结构体，只输出它们的 Builder 警告，
+// strict 模式下存在警告时返回错误
+func reportWarnings(structs utils.Structs, builders utils.Structs) error {
+	warnings := append(structs.Warnings(), builders.BuilderWarnings()...)
+	warnings.Sort()
+
+	for _, warning := range warnings {
+		pos := warning.Pos
+		pos.Filename = displayPath(pos.Filename)
+
+		fmt.Fprintf(os.Stderr, "%s: warning: %s [%s]\n", pos, warning.Message, warning.Reason)
+	}
+
+	if viper.GetBool("strict") && len(warnings) != 0 {
+		return fmt.Errorf("%d warning(s) found in strict mode", len(warnings))
+	}
+
+	return nil
+}
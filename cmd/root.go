@@ -18,6 +18,7 @@ package cmd
 import (
 	"bytes"
 	"fmt"
+	"github.com/ImSingee/god/utils"
 	"github.com/spf13/cobra"
 	"os"
 	"text/template"
@@ -29,8 +30,9 @@ var cfgFile string
 
 // rootCmd represents the base command when called without any subcommands
 var rootCmd = &cobra.Command{
-	Use:   "generate",
-	Short: "A code generator for go",
+	Use:     "generate",
+	Short:   "A code generator for go",
+	Version: utils.VERSION,
 	Long: `Please use 'go:generate' to run this app. 
 	
 	Add this to your source file (struct_name.go, for example):
@@ -40,10 +42,23 @@ var rootCmd = &cobra.Command{
 		go generate
 
 	Then a file "struct_name_getter.go" will be generated
+
+	Without a subcommand, code is generated for every struct annotated
+	with //god:getter, //god:setter or //god:builder in the package.
 	`,
+	RunE: runAnnotated,
 	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		// 不同子命令有同名的 flag（例如 struct），需要绑定实际执行的命令的 flag
+		_ = viper.BindPFlags(cmd.Flags())
+
 		_ = os.Chdir(viper.GetString("workdir"))
 
+		_, err := utils.LoadConfig(".", cfgFile)
+
+		if err != nil {
+			return err
+		}
+
 		if viper.GetBool("debug") {
 			info, err := GetBasicInfo()
 
@@ -57,6 +72,11 @@ var rootCmd = &cobra.Command{
 		return nil
 	},
 	PersistentPostRun: func(cmd *cobra.Command, args []string) {
+		// 输出代码或 diff 时不应混入其他内容
+		if isStdout() || isDryRun() {
+			return
+		}
+
 		fmt.Println("Done!")
 	},
 }
@@ -119,22 +139,48 @@ Configs:
 
 // Execute adds all child commands to the root command and sets flags appropriately.
 // This is called by main.main(). It only needs to happen once to the rootCmd.
+// 错误由这里输出一次，不再由 cobra 输出错误与用法
 func Execute() {
+	rootCmd.SilenceUsage = true
+	rootCmd.SilenceErrors = true
+
 	if err := rootCmd.Execute(); err != nil {
-		fmt.Println(err)
+		fmt.Fprintln(os.Stderr, "Error:", err)
 		os.Exit(1)
 	}
 }
 
 func init() {
+	rootCmd.PersistentFlags().StringVarP(&cfgFile, "config", "", "", "config file (default: "+utils.CONFIG_FILENAME+" found by walking up from the work directory)")
 	rootCmd.PersistentFlags().StringP("gofile", "", "", "mock Environment value")
 	rootCmd.PersistentFlags().StringP("gopackage", "", "", "mock Environment value")
 	rootCmd.PersistentFlags().StringP("workdir", "w", ".", "work directory")
-	rootCmd.PersistentFlags().StringP("filename", "", "{{ $.struct.LowerName }}_{{ $.type }}.go", "")
+	rootCmd.PersistentFlags().StringP("filename", "", utils.DEFAULT_FILENAME, "")
+	rootCmd.PersistentFlags().StringSliceP("initialisms", "", []string{}, "extra initialisms used when deriving method names (e.g. GRPC)")
+	rootCmd.PersistentFlags().StringP("naming", "", "go", "naming strategy for accessors: go (Name/SetName) or java (GetName/SetName)")
+	rootCmd.PersistentFlags().StringP("bool-prefix", "", "", "getter prefix for bool fields: is or has")
+	rootCmd.PersistentFlags().StringP("getter-name", "", "", "template for getter names, overrides --naming")
+	rootCmd.PersistentFlags().StringP("setter-name", "", "", "template for setter names, overrides --naming")
+	rootCmd.PersistentFlags().StringP("templates", "", "", "directory of custom templates (<name>.tmpl) overriding or adding generators")
+	rootCmd.PersistentFlags().StringP("sort", "", "source", "order of structs and fields in generated code: source or alpha")
 
 	rootCmd.PersistentFlags().BoolP("debug", "", false, "debug mode")
+	rootCmd.PersistentFlags().BoolP("strict", "", false, "treat warnings as errors")
+	rootCmd.PersistentFlags().BoolP("check", "", false, "verify generated files are up to date without writing them")
+	rootCmd.PersistentFlags().BoolP("dry-run", "", false, "print a unified diff against existing files instead of writing them")
+	rootCmd.PersistentFlags().BoolP("diff", "", false, "same as --dry-run")
+	rootCmd.PersistentFlags().StringP("output", "o", "", "write generated code to stdout with -o -, or into a zip archive with -o <name>.zip")
+	rootCmd.PersistentFlags().BoolP("single-file", "", false, "write all generated code into one file per package")
+	rootCmd.PersistentFlags().StringP("single-filename", "", "zz_god_generated.go", "filename used by --single-file")
+	rootCmd.PersistentFlags().BoolP("all", "a", false, "generate for all structs in the package")
+	rootCmd.PersistentFlags().BoolP("prune", "", false, "remove generated files whose source structs or generators no longer exist")
+	rootCmd.PersistentFlags().IntP("jobs", "j", 0, "number of concurrent generation jobs (default: number of CPUs)")
+	rootCmd.PersistentFlags().StringP("cache-dir", "", "", "directory of the generation cache (default: $XDG_CACHE_HOME/god)")
+	rootCmd.PersistentFlags().BoolP("no-cache", "", false, "always regenerate, ignoring the generation cache")
+	rootCmd.PersistentFlags().BoolP("no-typecheck", "", false, "write generated code without checking that the package still compiles")
 
 	_ = viper.BindPFlags(rootCmd.PersistentFlags())
+	viper.SetDefault("generators", []string{"getter", "setter"})
 	_ = viper.BindEnv("GOARCH")
 	_ = viper.BindEnv("GOOS")
 	_ = viper.BindEnv("GOFILE")
@@ -0,0 +1,448 @@
+/*
+Copyright © 2020 Singee <i@singee.me>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"github.com/ImSingee/god/utils"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"go/build"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// runCmd represents the run command
+var runCmd = &cobra.Command{
+	Use:   "run [packages]",
+	Short: "Run god directives of all matched packages (e.g. ./...) in one process",
+	Long: `Run finds every "//go:generate god ..." directive in the matched packages and
+executes it in-process, which is much faster than "go generate ./..." spawning
+a process per directive.
+
+Packages are given as directories, "dir/..." matches dir and all its subdirectories.
+Flags given to run are used as defaults for every directive.`,
+	RunE: runRun,
+}
+
+func init() {
+	rootCmd.AddCommand(runCmd)
+}
+
+// directive 是源码中的一条 go:generate 指令
+type directive struct {
+	Dir     string // 包所在的目录
+	Package string
+	File    string // 文件名（相对于 Dir）
+	Line    int
+	Args    []string // 不含开头的 god
+}
+
+func (d *directive) String() string {
+	return fmt.Sprintf("%s:%d", filepath.Join(d.Dir, d.File), d.Line)
+}
+
+func runRun(cmd *cobra.Command, args []string) error {
+	if len(args) == 0 {
+		args = []string{"."}
+	}
+
+	dirs, err := findPackageDirs(args)
+
+	if err != nil {
+		return err
+	}
+
+	// run 命令上的 flag 作为每条指令的默认值
+	return runDirectives(dirs, snapshotFlags(rootCmd.PersistentFlags()))
+}
+
+// runDirectives 执行所有目录中的指令，states 是每条指令的 flag 默认值
+func runDirectives(dirs []string, states map[string]flagState) error {
+	err := absolutePathFlags(states)
+
+	if err != nil {
+		return err
+	}
+
+	directives := make([]*directive, 0)
+
+	for _, dir := range dirs {
+		ds, err := findDirectives(dir)
+
+		if err != nil {
+			return err
+		}
+
+		directives = append(directives, ds...)
+	}
+
+	restoreEnv := saveEnv("GOFILE", "GOLINE", "GOPACKAGE")
+	defer restoreEnv()
+
+	wd, err := os.Getwd()
+
+	if err != nil {
+		return err
+	}
+
+	// 所有指令生成的文件在最后统一并发地格式化与保存
+	pendingOutput = &pendingFiles{wd: wd}
+	defer func() {
+		pendingOutput = nil
+	}()
+
+	errs := make(utils.Errors, 0)
+
+	for _, d := range directives {
+		err := executeDirective(d, states)
+
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", d, err))
+		}
+	}
+
+	err = resetFlags(rootCmd.PersistentFlags(), states)
+
+	if err != nil {
+		return fmt.Errorf("cannot reset flags: %w", err)
+	}
+
+	_, err = utils.LoadConfig(".", cfgFile)
+
+	if err != nil {
+		return err
+	}
+
+	files := pendingOutput.files
+	pendingOutput = nil
+
+	if err := emitFiles(files); err != nil {
+		errs = append(errs, err)
+	}
+
+	return errs.ErrorOrNil()
+}
+
+// findPackageDirs 根据参数找到所有包含 Go 文件的目录，dir/... 会匹配 dir 及其所有子目录
+func findPackageDirs(patterns []string) ([]string, error) {
+	dirs := make([]string, 0)
+	seen := make(map[string]bool)
+
+	add := func(dir string) error {
+		dir = filepath.Clean(dir)
+		if seen[dir] {
+			return nil
+		}
+		seen[dir] = true
+
+		_, err := build.ImportDir(dir, 0)
+
+		var noGoError *build.NoGoError
+		if errors.As(err, &noGoError) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("cannot import package %s: %w", dir, err)
+		}
+
+		dirs = append(dirs, dir)
+
+		return nil
+	}
+
+	for _, pattern := range patterns {
+		if pattern != "..." && !strings.HasSuffix(pattern, "/...") {
+			if err := add(pattern); err != nil {
+				return nil, err
+			}
+
+			continue
+		}
+
+		root := strings.TrimSuffix(strings.TrimSuffix(pattern, "..."), "/")
+		if root == "" {
+			root = "."
+		}
+
+		err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			if !info.IsDir() {
+				return nil
+			}
+
+			name := info.Name()
+			if path != root && (strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_") || name == "testdata" || name == "vendor") {
+				return filepath.SkipDir
+			}
+
+			return add(path)
+		})
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return dirs, nil
+}
+
+// findDirectives 找到目录中所有调用 god 的 go:generate 指令
+func findDirectives(dir string) ([]*directive, error) {
+	pkg, err := build.ImportDir(dir, 0)
+
+	if err != nil {
+		return nil, fmt.Errorf("cannot import package %s: %w", dir, err)
+	}
+
+	directives := make([]*directive, 0)
+
+	for _, filename := range pkg.GoFiles {
+		ds, err := findDirectivesInFile(dir, pkg.Name, filename)
+
+		if err != nil {
+			return nil, err
+		}
+
+		directives = append(directives, ds...)
+	}
+
+	return directives, nil
+}
+
+func findDirectivesInFile(dir, pkgName, filename string) ([]*directive, error) {
+	f, err := os.Open(filepath.Join(dir, filename))
+
+	if err != nil {
+		return nil, fmt.Errorf("cannot read file %s: %w", filename, err)
+	}
+	defer f.Close()
+
+	directives := make([]*directive, 0)
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	line := 0
+
+	for scanner.Scan() {
+		line++
+
+		text := scanner.Text()
+		if !strings.HasPrefix(text, "//go:generate ") && !strings.HasPrefix(text, "//go:generate\t") {
+			continue
+		}
+
+		d := &directive{
+			Dir:     dir,
+			Package: pkgName,
+			File:    filename,
+			Line:    line,
+		}
+
+		words, err := splitDirective(text[len("//go:generate "):], d)
+
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", d, err)
+		}
+
+		if len(words) == 0 || filepath.Base(words[0]) != "god" {
+			continue
+		}
+
+		d.Args = words[1:]
+		directives = append(directives, d)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("cannot read file %s: %w", filename, err)
+	}
+
+	return directives, nil
+}
+
+// splitDirective 与 go generate 相同：按空白拆分，支持双引号字符串，并展开 $GOFILE 等环境变量
+func splitDirective(line string, d *directive) ([]string, error) {
+	words := make([]string, 0)
+	line = strings.TrimSpace(line)
+
+	for line != "" {
+		if line[0] == '"' {
+			end := 1
+			for ; end < len(line); end++ {
+				if line[end] == '\\' {
+					end++
+					continue
+				}
+				if line[end] == '"' {
+					break
+				}
+			}
+
+			if end >= len(line) {
+				return nil, fmt.Errorf("unterminated quoted string")
+			}
+
+			word, err := strconv.Unquote(line[:end+1])
+
+			if err != nil {
+				return nil, fmt.Errorf("invalid quoted string %s: %w", line[:end+1], err)
+			}
+
+			words = append(words, word)
+			line = strings.TrimSpace(line[end+1:])
+
+			continue
+		}
+
+		end := strings.IndexAny(line, " \t")
+		if end == -1 {
+			end = len(line)
+		}
+
+		words = append(words, line[:end])
+		line = strings.TrimSpace(line[end:])
+	}
+
+	for i, word := range words {
+		words[i] = os.Expand(word, func(name string) string {
+			switch name {
+			case "GOFILE":
+				return d.File
+			case "GOLINE":
+				return strconv.Itoa(d.Line)
+			case "GOPACKAGE":
+				return d.Package
+			case "GOARCH":
+				return runtime.GOARCH
+			case "GOOS":
+				return runtime.GOOS
+			case "DOLLAR":
+				return "$"
+			default:
+				return os.Getenv(name)
+			}
+		})
+	}
+
+	return words, nil
+}
+
+// executeDirective 在包的目录中执行一条指令，与 go generate 一样通过环境变量传递 GOFILE 等信息
+func executeDirective(d *directive, states map[string]flagState) error {
+	sub, args, err := rootCmd.Find(d.Args)
+
+	if err != nil {
+		return err
+	}
+
+	if sub.Name() == "run" || sub.RunE == nil {
+		return fmt.Errorf("unsupported command: god %s", strings.Join(d.Args, " "))
+	}
+
+	err = resetFlags(sub.Flags(), states)
+
+	if err != nil {
+		return fmt.Errorf("cannot reset flags: %w", err)
+	}
+
+	err = sub.ParseFlags(args)
+
+	if err != nil {
+		return err
+	}
+
+	_ = viper.BindPFlags(sub.Flags())
+
+	wd, err := os.Getwd()
+
+	if err != nil {
+		return err
+	}
+
+	err = os.Chdir(d.Dir)
+
+	if err != nil {
+		return fmt.Errorf("cannot change directory to %s: %w", d.Dir, err)
+	}
+	defer os.Chdir(wd)
+
+	_, err = utils.LoadConfig(".", cfgFile)
+
+	if err != nil {
+		return err
+	}
+
+	_ = os.Setenv("GOFILE", d.File)
+	_ = os.Setenv("GOLINE", strconv.Itoa(d.Line))
+	_ = os.Setenv("GOPACKAGE", d.Package)
+
+	fmt.Println(strings.TrimSpace(fmt.Sprintf("%s: god %s", d, strings.Join(d.Args, " "))))
+
+	return sub.RunE(sub, sub.Flags().Args())
+}
+
+// pathFlags 是值为路径的 flag，指令在包所在的目录中执行，因此 run 命令上的相对路径需要先转换为绝对路径
+var pathFlags = []string{"config", "templates", "cache-dir"}
+
+// absolutePathFlags 将 states 中 pathFlags 的相对路径转换为相对于当前目录的绝对路径
+func absolutePathFlags(states map[string]flagState) error {
+	for _, name := range pathFlags {
+		state, ok := states[name]
+		if !ok || state.value == "" || filepath.IsAbs(state.value) {
+			continue
+		}
+
+		value, err := filepath.Abs(state.value)
+
+		if err != nil {
+			return fmt.Errorf("cannot resolve --%s %s: %w", name, state.value, err)
+		}
+
+		state.value = value
+		states[name] = state
+	}
+
+	return nil
+}
+
+// saveEnv 记录环境变量的值，返回用于恢复的函数
+func saveEnv(keys ...string) func() {
+	values := make(map[string]*string, len(keys))
+
+	for _, key := range keys {
+		if value, ok := os.LookupEnv(key); ok {
+			values[key] = &value
+		} else {
+			values[key] = nil
+		}
+	}
+
+	return func() {
+		for key, value := range values {
+			if value == nil {
+				_ = os.Unsetenv(key)
+			} else {
+				_ = os.Setenv(key, *value)
+			}
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package cmd_test
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRun(t *testing.T) {
+	dir := newModule(t, map[string]string{
+		"model/user.go":    "package model\n\n//go:generate god getter\n//go:generate stringer -type User\n\n" + strings.TrimPrefix(userSource, "package model\n\n"),
+		"order/order.go":   "package order\n\n//go:generate god setter -t Order\n\ntype Order struct {\n\tid int\n}\n\ntype Item struct {\n\tid int\n}\n",
+		"testdata/skip.go": "package skip\n\n//go:generate god getter -a\n\ntype Skip struct {\n\tid int\n}\n",
+	})
+
+	// 指令在包所在的目录中执行，GOFILE 与 GOPACKAGE 来自指令所在的文件
+	r := god(t, dir, "run", "./...")
+	r.expect(t, 0,
+		filepath.Join("model", "user.go")+":3: god getter",
+		filepath.Join("order", "order.go")+":3: god setter -t Order",
+	)
+
+	if strings.Contains(r.stdout, "stringer") || strings.Contains(r.stdout, "skip") {
+		t.Errorf("only god directives outside testdata should be executed:\n%s", r.stdout)
+	}
+
+	if !fileExists(filepath.Join(dir, "model", "user_getter.go")) {
+		t.Error("model/user_getter.go should be generated")
+	}
+
+	if !strings.Contains(readFile(t, filepath.Join(dir, "order", "order_setter.go")), "package order") {
+		t.Error("order/order_setter.go should use the package of the directive")
+	}
+
+	if fileExists(filepath.Join(dir, "order", "item_setter.go")) {
+		t.Error("only Order should be generated in order")
+	}
+
+	if fileExists(filepath.Join(dir, "testdata", "skip_getter.go")) {
+		t.Error("testdata should be skipped")
+	}
+
+	// run 命令上的 flag 作为每条指令的默认值
+	god(t, dir, "run", "./...", "--check").expect(t, 0)
+
+	writeFile(t, filepath.Join(dir, "order", "order_setter.go"), "package order\n")
+	god(t, dir, "run", "./...", "--check").expect(t, 1, "stale: "+filepath.Join("order", "order_setter.go"))
+}
+
+func TestRunUnsupportedCommand(t *testing.T) {
+	dir := newModule(t, map[string]string{
+		"user.go": "package model\n\n//go:generate god run\n",
+	})
+
+	r := god(t, dir, "run")
+	r.expect(t, 1)
+
+	if !strings.Contains(r.stderr, "user.go:3: unsupported command: god run") {
+		t.Errorf("unexpected stderr: %s", r.stderr)
+	}
+}
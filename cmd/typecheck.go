@@ -0,0 +1,57 @@
+/*
+Copyright © 2020 Singee <i@singee.me>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"fmt"
+	"github.com/ImSingee/god/utils"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// typeCheckFiles 按照目录将生成的文件叠加到所在的包中并发地进行类型检查，依赖的包在所有目录之间共享
+func typeCheckFiles(files []*generatedFile) error {
+	packages := make(map[string][]*utils.GeneratedFile)
+	for _, file := range files {
+		dir := filepath.Dir(file.Filename)
+		packages[dir] = append(packages[dir], &utils.GeneratedFile{
+			Filename:  file.Filename,
+			Content:   file.Formatted,
+			Generator: file.Type,
+			Struct:    file.Struct,
+			Replaces:  file.replaces,
+		})
+	}
+
+	dirs := make([]string, 0, len(packages))
+	for dir := range packages {
+		dirs = append(dirs, dir)
+	}
+	sort.Strings(dirs)
+
+	checker := utils.NewTypeChecker()
+
+	err := utils.RunParallel(optionsFromViper().Workers(), len(dirs), func(i int) error {
+		return checker.CheckFiles(os.DirFS(dirs[i]), ".", dirs[i], packages[dirs[i]])
+	})
+
+	if err != nil {
+		return fmt.Errorf("generated code does not compile, nothing is written (use --no-typecheck to skip the check):\n%w", err)
+	}
+
+	return nil
+}
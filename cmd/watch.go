@@ -0,0 +1,182 @@
+/*
+Copyright © 2020 Singee <i@singee.me>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"fmt"
+	"github.com/ImSingee/god/utils"
+	"github.com/fsnotify/fsnotify"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// watchCmd represents the watch command
+var watchCmd = &cobra.Command{
+	Use:   "watch [packages]",
+	Short: "Regenerate when struct definitions or method sets of the packages change",
+	Long: `Watch runs the god directives of the matched packages (like "god run"), then
+monitors their Go files and re-runs the directives of a package whenever its
+struct definitions, method sets or directives change.
+
+Files with the "Code generated by god" header are ignored to avoid loops.`,
+	RunE: runWatch,
+	// 持续运行，不输出 Done!
+	PersistentPostRun: func(cmd *cobra.Command, args []string) {},
+}
+
+func init() {
+	rootCmd.AddCommand(watchCmd)
+
+	watchCmd.Flags().DurationP("debounce", "", 300*time.Millisecond, "wait for this long after the last change before regenerating")
+
+	_ = viper.BindPFlags(watchCmd.Flags())
+}
+
+// watchedPackage 记录包上一次生成时的签名
+type watchedPackage struct {
+	signature string
+	generated map[string]bool // 由 god 生成的文件
+}
+
+func runWatch(cmd *cobra.Command, args []string) error {
+	if len(args) == 0 {
+		args = []string{"."}
+	}
+
+	dirs, err := findPackageDirs(args)
+
+	if err != nil {
+		return err
+	}
+
+	states := snapshotFlags(rootCmd.PersistentFlags())
+	debounce := viper.GetDuration("debounce")
+
+	watcher, err := fsnotify.NewWatcher()
+
+	if err != nil {
+		return fmt.Errorf("cannot create watcher: %w", err)
+	}
+	defer watcher.Close()
+
+	packages := make(map[string]*watchedPackage, len(dirs))
+
+	for _, dir := range dirs {
+		if err := watcher.Add(dir); err != nil {
+			return fmt.Errorf("cannot watch %s: %w", dir, err)
+		}
+
+		packages[dir] = &watchedPackage{}
+		updateSignature(dir, packages[dir])
+	}
+
+	if err := runDirectives(dirs, states); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+	}
+
+	// 生成的文件可能改变了签名（例如新增的方法）
+	for _, dir := range dirs {
+		updateSignature(dir, packages[dir])
+	}
+
+	fmt.Printf("Watching %d package(s), press Ctrl+C to stop\n", len(dirs))
+
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+	defer signal.Stop(interrupt)
+
+	changed := make(map[string]bool)
+	timer := time.NewTimer(debounce)
+	timer.Stop()
+
+	for {
+		select {
+		case <-interrupt:
+			return nil
+		case err := <-watcher.Errors:
+			fmt.Fprintf(os.Stderr, "watch error: %s\n", err)
+		case event := <-watcher.Events:
+			name := filepath.Clean(event.Name)
+			if !strings.HasSuffix(name, ".go") {
+				continue
+			}
+
+			dir := filepath.Dir(name)
+			pkg, ok := packages[dir]
+
+			if !ok || pkg.generated[name] {
+				continue
+			}
+
+			changed[dir] = true
+			timer.Reset(debounce)
+		case <-timer.C:
+			dirs := make([]string, 0, len(changed))
+
+			for dir := range changed {
+				pkg := packages[dir]
+				old := pkg.signature
+
+				if !updateSignature(dir, pkg) || pkg.signature == old {
+					continue
+				}
+
+				dirs = append(dirs, dir)
+			}
+
+			changed = make(map[string]bool)
+
+			if len(dirs) == 0 {
+				continue
+			}
+
+			sort.Strings(dirs)
+			fmt.Printf("[%s] %s changed\n", time.Now().Format("15:04:05"), strings.Join(dirs, ", "))
+
+			if err := runDirectives(dirs, states); err != nil {
+				fmt.Fprintln(os.Stderr, err)
+			}
+
+			for _, dir := range dirs {
+				updateSignature(dir, packages[dir])
+			}
+		}
+	}
+}
+
+// updateSignature 重新计算包的签名，失败时（例如文件正在编辑导致语法错误）输出错误并返回 false
+func updateSignature(dir string, pkg *watchedPackage) bool {
+	signature, generated, err := utils.PackageSignature(dir)
+
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return false
+	}
+
+	pkg.signature = signature
+	pkg.generated = make(map[string]bool, len(generated))
+	for _, filename := range generated {
+		pkg.generated[filename] = true
+	}
+
+	return true
+}
@@ -0,0 +1,85 @@
+package cmd_test
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWatch(t *testing.T) {
+	source := "package model\n\n//go:generate god getter\n\n" + strings.TrimPrefix(userSource, "package model\n\n")
+	dir := newModule(t, map[string]string{"user.go": source})
+	filename := filepath.Join(dir, "user_getter.go")
+
+	c := godCommand(t, dir, "watch", "--debounce", "50ms")
+
+	stdout, err := c.StdoutPipe()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := c.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer c.Process.Kill()
+
+	lines := make(chan string)
+	go func() {
+		scanner := bufio.NewScanner(stdout)
+		for scanner.Scan() {
+			lines <- scanner.Text()
+		}
+		close(lines)
+	}()
+
+	// waitFor 等待包含 s 的输出
+	waitFor := func(s string) {
+		t.Helper()
+
+		timeout := time.After(10 * time.Second)
+
+		for {
+			select {
+			case line, ok := <-lines:
+				if !ok {
+					t.Fatalf("watch exited before printing %q", s)
+				}
+				if strings.Contains(line, s) {
+					return
+				}
+			case <-timeout:
+				t.Fatalf("timeout waiting for %q", s)
+			}
+		}
+	}
+
+	waitFor("Watching 1 package(s)")
+
+	if !strings.Contains(readFile(t, filename), "func (u *User) Name() string {") {
+		t.Fatal("directives should be executed on start")
+	}
+
+	writeFile(t, filepath.Join(dir, "user.go"), strings.Replace(source, "Age  int", "Age  int\n\temail string", 1))
+
+	waitFor(". changed")
+	waitFor("save as user_getter.go")
+
+	if !strings.Contains(readFile(t, filename), "func (u *User) Email() string {") {
+		t.Error("user_getter.go should be regenerated after the struct changes")
+	}
+
+	if err := c.Process.Signal(os.Interrupt); err != nil {
+		t.Fatal(err)
+	}
+
+	for range lines {
+	}
+
+	if err := c.Wait(); err != nil {
+		t.Errorf("watch should exit normally on interrupt: %v", err)
+	}
+}
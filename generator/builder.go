@@ -0,0 +1,125 @@
+package generator
+
+import (
+	"fmt"
+	"github.com/ImSingee/god/utils"
+	"github.com/spf13/pflag"
+	"text/template"
+)
+
+var builderTemplate = template.Must(utils.GetTemplate("builder", `
+{{- $.header }}
+// Code generated by god builder, DO NOT EDIT.
+
+package {{ $.pkg }}
+
+{{ $.struct.ImportedStatements }}
+
+type {{ $.struct.Name }}Builder struct {
+	target {{ $.struct.Name }}
+}
+
+func New{{ $.struct.Name }}Builder() *{{ $.struct.Name }}Builder {
+	return &{{ $.struct.Name }}Builder{}
+}
+
+{{ range $_, $method := $.methods }}
+func ({{ $.receiver }} *{{ $.struct.Name }}Builder) {{ $method.Name }}({{ $method.Field.Name }} {{ $method.Field.Type }}) *{{ $.struct.Name }}Builder {
+	{{ $.receiver }}.target.{{ $method.Field.Name }} = {{ $method.Field.Name }}
+	return {{ $.receiver }}
+}
+{{ end }}
+
+{{- if $.build }}
+func ({{ $.receiver }} *{{ $.struct.Name }}Builder) Build() *{{ $.struct.Name }} {
+	result := {{ $.receiver }}.target
+	return &result
+}
+{{- end }}
+`, nil))
+
+// builderMethod 是 Builder 上设置一个字段的方法
+type builderMethod struct {
+	Name  string
+	Field *utils.Field
+}
+
+// BUILDER_PREFIX 是 Builder 方法名默认的前缀，可以通过 Options.BuilderPrefix（builder-prefix）修改
+const BUILDER_PREFIX = utils.DEFAULT_BUILDER_PREFIX
+
+// getBuilderMethods 返回 Builder 中需要生成的方法，方法名在加载包时推导（参考 Field.BuilderName），
+// 已经存在或者相互冲突的方法不会生成
+func getBuilderMethods(s *utils.Struct) []*builderMethod {
+	methods := make([]*builderMethod, 0, len(s.FieldList))
+
+	for _, field := range s.FieldList {
+		if !field.WillGenerateBuilder {
+			continue
+		}
+
+		methods = append(methods, &builderMethod{
+			Name:  field.BuilderName,
+			Field: field,
+		})
+	}
+
+	return methods
+}
+
+// getBuilderReceiver 返回 Builder 方法的接收者名称，方法的参数与字段同名，因此接收者不能与任何字段同名，也不能是 Build 中使用的 result
+func getBuilderReceiver(s *utils.Struct) string {
+	used := map[string]bool{"result": true}
+	for _, field := range s.FieldList {
+		used[field.Name] = true
+	}
+
+	for _, name := range []string{"b", "builder"} {
+		if !used[name] {
+			return name
+		}
+	}
+
+	for i := 1; ; i++ {
+		if name := fmt.Sprintf("b%d", i); !used[name] {
+			return name
+		}
+	}
+}
+
+func init() {
+	Register(builder{})
+}
+
+type builder struct{}
+
+func (builder) Name() string {
+	return "builder"
+}
+
+func (builder) Flags(flags *pflag.FlagSet) {
+	flags.StringP("builder-prefix", "", BUILDER_PREFIX, "prefix of builder methods")
+}
+
+// Applicable 在包中已经定义了 XBuilder 或 NewXBuilder 时为 false
+func (builder) Applicable(s *utils.Struct) bool {
+	return s.BuilderSkipped == nil
+}
+
+func (builder) Generate(s *utils.Struct) ([]byte, error) {
+	t, err := getTemplate(s.Package.Options, "builder", builderTemplate)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return executeBuilder(t, s)
+}
+
+// executeBuilder 执行 Builder 的模板，除了通用的数据外还有 methods、receiver 与 build（是否需要生成 Build 方法）
+func executeBuilder(t *template.Template, s *utils.Struct) ([]byte, error) {
+	return executeTemplate(t, s, map[string]interface{}{
+		"methods":  getBuilderMethods(s),
+		"receiver": getBuilderReceiver(s),
+		"build":    !s.BuildExists,
+	})
+}
@@ -0,0 +1,151 @@
+package generator
+
+import (
+	"fmt"
+	"github.com/ImSingee/god/plugin"
+	"github.com/ImSingee/god/utils"
+	"github.com/spf13/pflag"
+	"sort"
+	"sync"
+)
+
+// Generator 是一种代码生成器，为每个结构体生成一个文件
+type Generator interface {
+	// Name 是生成器的名称，同时也是命令的名称与生成的文件名中的类型
+	Name() string
+	// Flags 注册生成器自己的命令行参数，参数的值可以通过 Setting 读取
+	Flags(flags *pflag.FlagSet)
+	// Applicable 判断是否需要为结构体生成代码，例如没有需要生成 Getter 的字段时不生成文件
+	Applicable(s *utils.Struct) bool
+	// Generate 为结构体生成未格式化的代码
+	Generate(s *utils.Struct) ([]byte, error)
+}
+
+// PackageGenerator 可以一次为包中的多个结构体生成代码，例如外部生成器只需要启动一次
+type PackageGenerator interface {
+	Generator
+	GeneratePackage(pkg *utils.Package, structs []*utils.Struct) (map[*utils.Struct][]byte, error)
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   = make(map[string]Generator)
+)
+
+// Register 注册生成器，名称重复时 panic
+func Register(g Generator) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if _, ok := registry[g.Name()]; ok {
+		panic("generator: Register called twice for generator " + g.Name())
+	}
+
+	registry[g.Name()] = g
+}
+
+// Registered 返回按名称排序的所有已注册的生成器
+func Registered() []Generator {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	list := make([]Generator, 0, len(registry))
+	for _, g := range registry {
+		list = append(list, g)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Name() < list[j].Name()
+	})
+
+	return list
+}
+
+// Lookup 查找生成器，依次查找已注册的生成器、模板目录中的模板与 PATH 中的外部生成器
+func Lookup(options *utils.Options, name string) (Generator, error) {
+	registryMu.RLock()
+	g, ok := registry[name]
+	registryMu.RUnlock()
+
+	if ok {
+		return g, nil
+	}
+
+	templates, err := CustomTemplates(options)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if _, ok := templates[name]; ok {
+		return &templateGenerator{name: name}, nil
+	}
+
+	if path, err := plugin.Lookup(name); err == nil {
+		return &pluginGenerator{name: name, path: path}, nil
+	}
+
+	return nil, fmt.Errorf("unknown generator %s", name)
+}
+
+// Exists 判断生成器是否存在，包括模板目录中的自定义生成器与 PATH 中的外部生成器
+func Exists(options *utils.Options, name string) bool {
+	_, err := Lookup(options, name)
+
+	return err == nil
+}
+
+// Generate 使用指定名称的生成器为需要的结构体生成代码
+func Generate(pkg *utils.Package, name string, structs utils.Structs) (map[*utils.Struct][]byte, error) {
+	g, err := Lookup(pkg.Options, name)
+
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]*utils.Struct, 0, len(structs))
+	for _, s := range structs.List() {
+		if g.Applicable(s) {
+			list = append(list, s)
+		}
+	}
+
+	if g, ok := g.(PackageGenerator); ok {
+		return g.GeneratePackage(pkg, list)
+	}
+
+	contents := make([][]byte, len(list))
+
+	err = utils.RunParallel(pkg.Options.Workers(), len(list), func(i int) error {
+		result, err := g.Generate(list[i])
+
+		if err != nil {
+			return fmt.Errorf("cannot generate %s for struct %s: %w", name, list[i].Name, err)
+		}
+
+		contents[i] = result
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	results := make(map[*utils.Struct][]byte, len(list))
+	for i, s := range list {
+		results[s] = contents[i]
+	}
+
+	return results, nil
+}
+
+// Setting 返回生成器的命令行参数（或配置）的值，没有设置时返回 fallback
+func Setting(options *utils.Options, key, fallback string) string {
+	if value, ok := options.AllSettings()[key]; ok {
+		if s := fmt.Sprint(value); s != "" {
+			return s
+		}
+	}
+
+	return fallback
+}
@@ -0,0 +1,18 @@
+package generator_test
+
+import (
+	"github.com/ImSingee/god/godtest"
+	"testing"
+)
+
+func TestGetter(t *testing.T) {
+	godtest.Run(t, "testdata/getter", nil, "getter")
+}
+
+func TestSetter(t *testing.T) {
+	godtest.Run(t, "testdata/setter", nil, "setter")
+}
+
+func TestBuilder(t *testing.T) {
+	godtest.Run(t, "testdata/builder", nil, "builder")
+}
@@ -1,57 +1,57 @@
 package generator
 
 import (
-	"bytes"
-	"fmt"
 	"github.com/ImSingee/god/utils"
-	"github.com/spf13/viper"
+	"github.com/spf13/pflag"
+	"text/template"
 )
 
-var getterTemplate = utils.GetTemplate("getter", `
+var getterTemplate = template.Must(utils.GetTemplate("getter", `
+{{- $.header }}
 // Code generated by god getter, DO NOT EDIT.
 
 package {{ $.pkg }}
 
 {{ $.struct.ImportedStatements }}
 
-{{ range $_, $field := $.struct.Fields }}
+{{ range $_, $field := $.struct.FieldList }}
 {{ if $field.WillGenerateGetter }}
 func ({{ $.struct.ShortName }} *{{ $.struct.Name }}) {{ $field.GetterName }}() {{ $field.Type }} {
 	return {{ $.struct.ShortName }}.{{ $field.Name }}
 }
 {{ end }}
 {{ end }}
-`)
+`, nil))
 
-func GenerateGetter(s *utils.Struct) ([]byte, error) {
-	packageName := viper.GetString("gopackage")
+func init() {
+	Register(getter{})
+}
 
-	w := bytes.NewBuffer(make([]byte, 0, 1024))
+type getter struct{}
 
-	err := getterTemplate.Execute(w, map[string]interface{}{
-		"pkg":    packageName,
-		"struct": s,
-	})
+func (getter) Name() string {
+	return "getter"
+}
 
-	if err != nil {
-		return nil, err
+func (getter) Flags(flags *pflag.FlagSet) {}
+
+// Applicable 没有需要生成 Getter 的字段时不生成文件
+func (getter) Applicable(s *utils.Struct) bool {
+	for _, field := range s.FieldList {
+		if field.WillGenerateGetter {
+			return true
+		}
 	}
 
-	return w.Bytes(), nil
+	return false
 }
 
-func GenerateGetters(structs utils.Structs) (map[*utils.Struct][]byte, error) {
-	results := make(map[*utils.Struct][]byte, len(structs))
+func (getter) Generate(s *utils.Struct) ([]byte, error) {
+	t, err := getTemplate(s.Package.Options, "getter", getterTemplate)
 
-	for _, s := range structs {
-		result, err := GenerateGetter(s)
-
-		if err != nil {
-			return nil, fmt.Errorf("cannot generate getter for struct %s: %w", s.Name, err)
-		}
-
-		results[s] = result
+	if err != nil {
+		return nil, err
 	}
 
-	return results, nil
+	return executeTemplate(t, s, nil)
 }
@@ -0,0 +1,67 @@
+package generator
+
+import (
+	"fmt"
+	"github.com/ImSingee/god/plugin"
+	"github.com/ImSingee/god/utils"
+	"github.com/spf13/pflag"
+)
+
+// pluginGenerator 使用 PATH 中的外部生成器 god-gen-<name> 生成代码，每个包只执行一次
+type pluginGenerator struct {
+	name string
+	path string
+}
+
+func (g *pluginGenerator) Name() string {
+	return g.name
+}
+
+func (g *pluginGenerator) Flags(flags *pflag.FlagSet) {}
+
+func (g *pluginGenerator) Applicable(s *utils.Struct) bool {
+	return true
+}
+
+func (g *pluginGenerator) Generate(s *utils.Struct) ([]byte, error) {
+	results, err := g.GeneratePackage(s.Package, []*utils.Struct{s})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return results[s], nil
+}
+
+func (g *pluginGenerator) GeneratePackage(pkg *utils.Package, structs []*utils.Struct) (map[*utils.Struct][]byte, error) {
+	request := plugin.NewRequest(g.name, pkg, structs)
+
+	response, err := plugin.Run(g.path, request)
+
+	if err != nil {
+		return nil, err
+	}
+
+	index := make(map[string]*utils.Struct, len(structs))
+	for _, s := range structs {
+		index[s.Name] = s
+	}
+
+	results := make(map[*utils.Struct][]byte, len(response.Files))
+
+	for _, file := range response.Files {
+		s, ok := index[file.Struct]
+
+		if !ok {
+			return nil, fmt.Errorf("generator %s returned a file for unknown struct %s", g.name, file.Struct)
+		}
+
+		if _, ok := results[s]; ok {
+			return nil, fmt.Errorf("generator %s returned multiple files for struct %s", g.name, file.Struct)
+		}
+
+		results[s] = []byte(file.Content)
+	}
+
+	return results, nil
+}
@@ -1,57 +1,57 @@
 package generator
 
 import (
-	"bytes"
-	"fmt"
 	"github.com/ImSingee/god/utils"
-	"github.com/spf13/viper"
+	"github.com/spf13/pflag"
+	"text/template"
 )
 
-var setterTemplate = utils.GetTemplate("setter", `
+var setterTemplate = template.Must(utils.GetTemplate("setter", `
+{{- $.header }}
 // Code generated by god setter, DO NOT EDIT.
 
 package {{ $.pkg }}
 
 {{ $.struct.ImportedStatements }}
 
-{{ range $_, $field := $.struct.Fields }}
+{{ range $_, $field := $.struct.FieldList }}
 {{ if $field.WillGenerateSetter }}
 func ({{ $.struct.ShortName }} *{{ $.struct.Name }}) {{ $field.SetterName }}({{ $field.Name }} {{ $field.Type }})  {
 	{{ $.struct.ShortName }}.{{ $field.Name }} = {{ $field.Name }}
 }
 {{ end }}
 {{ end }}
-`)
+`, nil))
 
-func GenerateSetter(s *utils.Struct) ([]byte, error) {
-	packageName := viper.GetString("gopackage")
+func init() {
+	Register(setter{})
+}
 
-	w := bytes.NewBuffer(make([]byte, 0, 1024))
+type setter struct{}
 
-	err := setterTemplate.Execute(w, map[string]interface{}{
-		"pkg":    packageName,
-		"struct": s,
-	})
+func (setter) Name() string {
+	return "setter"
+}
 
-	if err != nil {
-		return nil, err
+func (setter) Flags(flags *pflag.FlagSet) {}
+
+// Applicable 没有需要生成 Setter 的字段时不生成文件
+func (setter) Applicable(s *utils.Struct) bool {
+	for _, field := range s.FieldList {
+		if field.WillGenerateSetter {
+			return true
+		}
 	}
 
-	return w.Bytes(), nil
+	return false
 }
 
-func GenerateSetters(structs utils.Structs) (map[*utils.Struct][]byte, error) {
-	results := make(map[*utils.Struct][]byte, len(structs))
+func (setter) Generate(s *utils.Struct) ([]byte, error) {
+	t, err := getTemplate(s.Package.Options, "setter", setterTemplate)
 
-	for _, s := range structs {
-		result, err := GenerateSetter(s)
-
-		if err != nil {
-			return nil, fmt.Errorf("cannot generate setter for struct %s: %w", s.Name, err)
-		}
-
-		results[s] = result
+	if err != nil {
+		return nil, err
 	}
 
-	return results, nil
+	return executeTemplate(t, s, nil)
 }
@@ -0,0 +1,150 @@
+package generator
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/ImSingee/god/utils"
+	"github.com/spf13/pflag"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+// TEMPLATE_EXT 是自定义模板文件的扩展名
+//
+// 模板目录（templates 配置）中的 getter.tmpl 等会替换同名的内置模板，其他的模板会作为新的生成器，
+// 通过 god gen <name> 使用，模板接收的数据与内置模板相同
+const TEMPLATE_EXT = ".tmpl"
+
+// CustomTemplates 返回模板目录（Options.Templates）中所有模板的名称与对应的文件，没有配置模板目录时返回空
+func CustomTemplates(options *utils.Options) (map[string]string, error) {
+	templates := make(map[string]string)
+
+	dir := options.Templates
+	if dir == "" {
+		return templates, nil
+	}
+
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		return nil, fmt.Errorf("cannot find templates directory %s", dir)
+	}
+
+	filenames, err := filepath.Glob(filepath.Join(dir, "*"+TEMPLATE_EXT))
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, filename := range filenames {
+		templates[strings.TrimSuffix(filepath.Base(filename), TEMPLATE_EXT)] = filename
+	}
+
+	return templates, nil
+}
+
+// TemplateFiles 返回按名称排序的所有自定义模板文件
+func TemplateFiles(options *utils.Options) ([]string, error) {
+	templates, err := CustomTemplates(options)
+
+	if err != nil {
+		return nil, err
+	}
+
+	filenames := make([]string, 0, len(templates))
+	for _, filename := range templates {
+		filenames = append(filenames, filename)
+	}
+	sort.Strings(filenames)
+
+	return filenames, nil
+}
+
+// getTemplate 返回生成器使用的模板，模板目录中存在同名模板时使用自定义的模板
+//
+// builtin 为 nil 表示没有内置的模板，模板中的函数会使用 options 中的缩写词
+func getTemplate(options *utils.Options, name string, builtin *template.Template) (*template.Template, error) {
+	templates, err := CustomTemplates(options)
+
+	if err != nil {
+		return nil, err
+	}
+
+	filename, ok := templates[name]
+	if !ok {
+		if builtin == nil {
+			return nil, fmt.Errorf("unknown generator %s", name)
+		}
+
+		t, err := builtin.Clone()
+
+		if err != nil {
+			return nil, err
+		}
+
+		return t.Funcs(utils.TemplateFuncs(options)), nil
+	}
+
+	content, err := ioutil.ReadFile(filename)
+
+	if err != nil {
+		return nil, fmt.Errorf("cannot read template %s: %w", filename, err)
+	}
+
+	t, err := template.New(name).Funcs(utils.TemplateFuncs(options)).Parse(string(content))
+
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse template %s: %w", filename, err)
+	}
+
+	return t, nil
+}
+
+// executeTemplate 使用 pkg、struct、header 以及 extra 中的数据执行模板
+func executeTemplate(t *template.Template, s *utils.Struct, extra map[string]interface{}) ([]byte, error) {
+	data := map[string]interface{}{
+		"pkg":    s.Package.Name,
+		"struct": s,
+		"header": s.Package.Options.HeaderComment(),
+	}
+	for key, value := range extra {
+		data[key] = value
+	}
+
+	w := bytes.NewBuffer(make([]byte, 0, 1024))
+
+	err := t.Execute(w, data)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return w.Bytes(), nil
+}
+
+// templateGenerator 使用模板目录中的 <name>.tmpl 生成代码
+type templateGenerator struct {
+	name string
+}
+
+func (g *templateGenerator) Name() string {
+	return g.name
+}
+
+func (g *templateGenerator) Flags(flags *pflag.FlagSet) {}
+
+func (g *templateGenerator) Applicable(s *utils.Struct) bool {
+	return true
+}
+
+func (g *templateGenerator) Generate(s *utils.Struct) ([]byte, error) {
+	t, err := getTemplate(s.Package.Options, g.name, nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return executeTemplate(t, s, nil)
+}
@@ -0,0 +1,9 @@
+package model
+
+import "time"
+
+type User struct {
+	name      string
+	createdAt time.Time
+	ignored   string //god:skip
+}
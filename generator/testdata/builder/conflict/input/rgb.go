@@ -0,0 +1,11 @@
+package model
+
+type RGB struct {
+	r, g, b uint8
+}
+
+type Result struct {
+	result  int
+	builder string
+	b       bool
+}
@@ -0,0 +1,31 @@
+// Code generated by god builder, DO NOT EDIT.
+
+package model
+
+type ResultBuilder struct {
+	target Result
+}
+
+func NewResultBuilder() *ResultBuilder {
+	return &ResultBuilder{}
+}
+
+func (b1 *ResultBuilder) WithResult(result int) *ResultBuilder {
+	b1.target.result = result
+	return b1
+}
+
+func (b1 *ResultBuilder) WithBuilder(builder string) *ResultBuilder {
+	b1.target.builder = builder
+	return b1
+}
+
+func (b1 *ResultBuilder) WithB(b bool) *ResultBuilder {
+	b1.target.b = b
+	return b1
+}
+
+func (b1 *ResultBuilder) Build() *Result {
+	result := b1.target
+	return &result
+}
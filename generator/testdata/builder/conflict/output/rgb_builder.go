@@ -0,0 +1,31 @@
+// Code generated by god builder, DO NOT EDIT.
+
+package model
+
+type RGBBuilder struct {
+	target RGB
+}
+
+func NewRGBBuilder() *RGBBuilder {
+	return &RGBBuilder{}
+}
+
+func (builder *RGBBuilder) WithR(r uint8) *RGBBuilder {
+	builder.target.r = r
+	return builder
+}
+
+func (builder *RGBBuilder) WithG(g uint8) *RGBBuilder {
+	builder.target.g = g
+	return builder
+}
+
+func (builder *RGBBuilder) WithB(b uint8) *RGBBuilder {
+	builder.target.b = b
+	return builder
+}
+
+func (builder *RGBBuilder) Build() *RGB {
+	result := builder.target
+	return &result
+}
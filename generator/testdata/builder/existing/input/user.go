@@ -0,0 +1,27 @@
+package model
+
+import "strings"
+
+type User struct {
+	name string
+	age  int
+}
+
+// WithName 与 Build 已经存在，不会重复生成
+func (b *UserBuilder) WithName(name string) *UserBuilder {
+	b.target.name = strings.TrimSpace(name)
+	return b
+}
+
+func (b *UserBuilder) Build() *User {
+	return &b.target
+}
+
+// Order 的 Builder 已经存在，不会生成
+type Order struct {
+	id int
+}
+
+type OrderBuilder interface {
+	Build() *Order
+}
@@ -0,0 +1,8 @@
+package model
+
+// field3 与 Field3 都会推导出 WithField3，两个方法都不会生成
+type User struct {
+	field3 int
+	Field3 string
+	name   string
+}
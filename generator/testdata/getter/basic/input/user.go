@@ -0,0 +1,17 @@
+package model
+
+import (
+	"net/http"
+	"time"
+)
+
+type User struct {
+	name      string
+	age       int
+	active    bool
+	createdAt time.Time
+	client    *http.Client
+	tags      []string
+	attrs     map[string]interface{}
+	Public    string
+}
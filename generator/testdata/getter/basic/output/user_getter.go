@@ -0,0 +1,36 @@
+// Code generated by god getter, DO NOT EDIT.
+
+package model
+
+import (
+	"net/http"
+	"time"
+)
+
+func (u *User) Name() string {
+	return u.name
+}
+
+func (u *User) Age() int {
+	return u.age
+}
+
+func (u *User) Active() bool {
+	return u.active
+}
+
+func (u *User) CreatedAt() time.Time {
+	return u.createdAt
+}
+
+func (u *User) Client() *http.Client {
+	return u.client
+}
+
+func (u *User) Tags() []string {
+	return u.tags
+}
+
+func (u *User) Attrs() map[string]interface{} {
+	return u.attrs
+}
@@ -0,0 +1,11 @@
+package model
+
+type User struct {
+	name  string
+	email string
+}
+
+// Name 已经存在，不会重复生成
+func (u *User) Name() string {
+	return u.name
+}
@@ -0,0 +1,9 @@
+package model
+
+type User struct {
+	userID     int
+	userIDList []int
+	url        string
+	httpServer string
+	utf8Reader string
+}
@@ -0,0 +1,23 @@
+// Code generated by god getter, DO NOT EDIT.
+
+package model
+
+func (u *User) UserID() int {
+	return u.userID
+}
+
+func (u *User) UserIDList() []int {
+	return u.userIDList
+}
+
+func (u *User) URL() string {
+	return u.url
+}
+
+func (u *User) HTTPServer() string {
+	return u.httpServer
+}
+
+func (u *User) UTF8Reader() string {
+	return u.utf8Reader
+}
@@ -0,0 +1,17 @@
+package model
+
+type User struct {
+	url    string
+	userID int
+	email  string
+}
+
+// Url 与推导出的 URL 只有大小写不同，不会生成 URL
+func (u *User) Url() string {
+	return u.url
+}
+
+// UserId 是不处理缩写词时的名称，不会生成 UserID
+func (u *User) UserId() int {
+	return u.userID
+}
@@ -0,0 +1,17 @@
+// Code generated by god setter, DO NOT EDIT.
+
+package model
+
+import "time"
+
+func (u *User) SetName(name string) {
+	u.name = name
+}
+
+func (u *User) SetCreatedAt(createdAt time.Time) {
+	u.createdAt = createdAt
+}
+
+func (u *User) SetScores(scores []int) {
+	u.scores = scores
+}
@@ -0,0 +1,8 @@
+package model
+
+type User struct {
+	id       int    `god:"name=ID"`
+	password string `god:"getter=-,setter=ChangePassword"`
+	token    string `setter:"disable"`
+	internal string //god:skip
+}
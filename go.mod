@@ -1,10 +1,12 @@
 module github.com/ImSingee/god
 
-go 1.14
+go 1.16
 
 require (
-	github.com/mitchellh/go-homedir v1.1.0
+	github.com/fsnotify/fsnotify v1.4.7
+	github.com/spf13/cast v1.3.0
 	github.com/spf13/cobra v1.0.0
+	github.com/spf13/pflag v1.0.3
 	github.com/spf13/viper v1.7.1
 	golang.org/x/tools v0.0.0-20191112195655-aa38f8e97acc
 )
@@ -0,0 +1,197 @@
+// Package god 是 god 的 Go API，可以在构建工具或测试中直接使用
+//
+// 与命令行不同，所有选项都通过 Options 显式传递，不会读取命令行参数、环境变量与配置文件，也不会改变工作目录：
+//
+//	pkg, err := god.Load("./model", &god.Options{All: true, Naming: "java"})
+//	if err != nil {
+//		return err
+//	}
+//
+//	files, err := god.Generate(pkg, "getter", "setter")
+//	if err != nil {
+//		return err
+//	}
+//
+//	return god.Save(files)
+//
+// 包也可以从任意的 fs.FS（例如内存中的 fstest.MapFS）加载，生成的文件可以写入任意的 Writer：
+//
+//	pkg, err := god.LoadFS(fsys, "model", &god.Options{All: true})
+//	...
+//	w := god.NewMemoryWriter()
+//	err = god.Write(w, files)
+package god
+
+import (
+	"fmt"
+	"github.com/ImSingee/god/generator"
+	"github.com/ImSingee/god/utils"
+	"io"
+	"io/fs"
+	"path/filepath"
+)
+
+type (
+	Options = utils.Options
+	Package = utils.Package
+	Struct  = utils.Struct
+	Field   = utils.Field
+
+	Writer       = utils.Writer
+	DiskWriter   = utils.DiskWriter
+	MemoryWriter = utils.MemoryWriter
+	ZipWriter    = utils.ZipWriter
+	StreamWriter = utils.StreamWriter
+)
+
+// File 是生成的文件
+type File struct {
+	Struct    *Struct
+	Generator string
+	Filename  string // 包含包所在的目录
+	Content   []byte // 格式化后的代码
+}
+
+// Load 加载 dir 中的包，options 为 nil 时使用默认选项（需要指定 File、Structs、All 或 Annotated 之一来选择结构体）
+func Load(dir string, options *Options) (*Package, error) {
+	return utils.LoadPackage(dir, options)
+}
+
+// LoadFS 与 Load 相同，但从 fsys 中的 dir（使用 / 分隔的路径）加载包，生成的文件名同样相对于 fsys
+func LoadFS(fsys fs.FS, dir string, options *Options) (*Package, error) {
+	return utils.LoadPackageFS(fsys, dir, options)
+}
+
+// NewMemoryWriter 返回将文件保存在内存中的 Writer
+func NewMemoryWriter() *MemoryWriter {
+	return utils.NewMemoryWriter()
+}
+
+// NewZipWriter 返回将文件写入 zip 压缩包的 Writer，所有文件写入后需要调用 Close
+func NewZipWriter(w io.Writer) *ZipWriter {
+	return utils.NewZipWriter(w)
+}
+
+// NewStreamWriter 返回将文件依次输出到 w 的 Writer，每个文件前会输出文件名
+func NewStreamWriter(w io.Writer) *StreamWriter {
+	return utils.NewStreamWriter(w)
+}
+
+// Generate 使用生成器为包中的结构体生成代码并格式化，生成器可以是内置的（getter、setter、builder）、
+// 模板目录中的模板或者 PATH 中的外部生成器，不指定时使用 getter 与 setter
+//
+// 生成的代码会与包一起进行类型检查（Options.NoTypeCheck 为 true 时跳过），无法编译时返回错误
+func Generate(pkg *Package, generators ...string) ([]*File, error) {
+	if len(generators) == 0 {
+		generators = []string{"getter", "setter"}
+	}
+
+	t, err := utils.GetTemplate("filename", pkg.Options.FilenameTemplate(), pkg.Options)
+
+	if err != nil {
+		return nil, err
+	}
+
+	files := make([]*File, 0)
+
+	for _, name := range generators {
+		results, err := generator.Generate(pkg, name, pkg.Structs)
+
+		if err != nil {
+			return nil, err
+		}
+
+		for _, s := range pkg.Structs.List() {
+			content, ok := results[s]
+			if !ok {
+				continue
+			}
+
+			filename, err := utils.ExecuteTemplate(t, map[string]interface{}{
+				"struct": s,
+				"type":   name,
+			})
+
+			if err != nil {
+				return nil, err
+			}
+
+			files = append(files, &File{
+				Struct:    s,
+				Generator: name,
+				Filename:  filepath.Join(pkg.Dir, filename),
+				Content:   content,
+			})
+		}
+	}
+
+	err = utils.RunParallel(pkg.Options.Workers(), len(files), func(i int) error {
+		content, err := utils.FormatGoCode(files[i].Filename, files[i].Content)
+
+		if err != nil {
+			return fmt.Errorf("cannot format generated code for %s: %w", files[i].Filename, err)
+		}
+
+		files[i].Content = content
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	if !pkg.Options.NoTypeCheck {
+		err = typeCheck(pkg, files)
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return files, nil
+}
+
+// typeCheck 将包目录中生成的文件叠加到包中进行类型检查
+func typeCheck(pkg *Package, files []*File) error {
+	generated := make([]*utils.GeneratedFile, 0, len(files))
+
+	for _, file := range files {
+		if filepath.Dir(file.Filename) != filepath.Clean(pkg.Dir) {
+			continue
+		}
+
+		generated = append(generated, &utils.GeneratedFile{
+			Filename:  file.Filename,
+			Content:   file.Content,
+			Generator: file.Generator,
+			Struct:    file.Struct,
+		})
+	}
+
+	err := pkg.CheckFiles(utils.NewTypeChecker(), generated)
+
+	if err != nil {
+		return fmt.Errorf("generated code does not compile:\n%w", err)
+	}
+
+	return nil
+}
+
+// Save 将生成的文件写入磁盘
+func Save(files []*File) error {
+	return Write(DiskWriter{}, files)
+}
+
+// Write 按顺序将生成的文件写入 w
+func Write(w Writer, files []*File) error {
+	for _, file := range files {
+		err := w.WriteFile(file.Filename, file.Content)
+
+		if err != nil {
+			return fmt.Errorf("cannot write file %s: %w", file.Filename, err)
+		}
+	}
+
+	return nil
+}
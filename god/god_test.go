@@ -0,0 +1,85 @@
+package god_test
+
+import (
+	"github.com/ImSingee/god/god"
+	"os"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestGenerateFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"model/user.go": &fstest.MapFile{Data: []byte("package model\n\ntype User struct {\n\tname string\n\tage  int\n}\n")},
+	}
+
+	pkg, err := god.LoadFS(fsys, "model", &god.Options{All: true, BuilderPrefix: "Set"})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	files, err := god.Generate(pkg, "getter", "builder")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	w := god.NewMemoryWriter()
+
+	err = god.Write(w, files)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	generated := w.Files()
+
+	want := map[string][]string{
+		"model/user_getter.go":  {"func (u *User) Name() string", "func (u *User) Age() int"},
+		"model/user_builder.go": {"func NewUserBuilder() *UserBuilder", ") SetName(name string) *UserBuilder", ") SetAge(age int) *UserBuilder"},
+	}
+
+	if len(generated) != len(want) {
+		t.Errorf("generated %d files, want %d", len(generated), len(want))
+	}
+
+	for filename, snippets := range want {
+		content, ok := generated[filename]
+		if !ok {
+			t.Errorf("%s is not generated", filename)
+			continue
+		}
+
+		for _, snippet := range snippets {
+			if !strings.Contains(string(content), snippet) {
+				t.Errorf("%s does not contain %q:\n%s", filename, snippet, content)
+			}
+		}
+	}
+}
+
+// 包中 import 了所在模块中的其他包，类型检查需要在包所在的目录（而不是当前目录）中解析 import
+func TestGenerateFSModuleImports(t *testing.T) {
+	pkg, err := god.LoadFS(os.DirFS("."), "testdata/module/model", &god.Options{All: true})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	files, err := god.Generate(pkg, "getter", "setter")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(files) != 2 {
+		t.Fatalf("generated %d files, want 2", len(files))
+	}
+
+	for _, file := range files {
+		if !strings.Contains(string(file.Content), `"example.com/module/types"`) {
+			t.Errorf("%s does not import example.com/module/types:\n%s", file.Filename, file.Content)
+		}
+	}
+}
@@ -0,0 +1,8 @@
+package model
+
+import "example.com/module/types"
+
+type User struct {
+	name  string
+	email types.Email
+}
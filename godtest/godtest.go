@@ -0,0 +1,248 @@
+// Package godtest 为生成器（内置的生成器、自定义模板与外部生成器）提供基于 golden 文件的测试
+//
+// dir 中的每个目录是一个用例，input 中是输入的包，output 中是期望生成的文件（文件名由 filename 模板决定）：
+//
+//	testdata/
+//	  user/
+//	    input/user.go
+//	    output/user_getter.go
+//	    output/user_setter.go
+//
+//	func TestGenerators(t *testing.T) {
+//		godtest.Run(t, "testdata", &god.Options{All: true}, "getter", "setter")
+//	}
+//
+// 生成的代码在内存中与输入的包一起进行类型检查，使用 go test -god.update 更新 golden 文件
+package godtest
+
+import (
+	"flag"
+	"fmt"
+	"github.com/ImSingee/god/god"
+	"github.com/ImSingee/god/utils"
+	"io/fs"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+)
+
+const (
+	INPUT_DIR  = "input"
+	OUTPUT_DIR = "output"
+)
+
+// UPDATE_FLAG 是更新 golden 文件的参数，使用 god 专用的名称以免与测试中的参数冲突
+const UPDATE_FLAG = "god.update"
+
+func init() {
+	flag.Bool(UPDATE_FLAG, false, "update golden files of god generators")
+}
+
+// updating 表示使用 -god.update 运行测试，测试中定义了 -update 参数时同样会使用它
+func updating() bool {
+	for _, name := range []string{UPDATE_FLAG, "update"} {
+		if f := flag.Lookup(name); f != nil && f.Value.String() == "true" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Run 对 dir 中的每个用例运行生成器并与 golden 文件比较，options 为 nil 时为包中所有的结构体生成代码
+func Run(t *testing.T, dir string, options *god.Options, generators ...string) {
+	t.Helper()
+
+	infos, err := ioutil.ReadDir(dir)
+
+	if err != nil {
+		t.Fatalf("cannot read test cases from %s: %s", dir, err)
+	}
+
+	found := false
+
+	for _, info := range infos {
+		if !info.IsDir() {
+			continue
+		}
+
+		found = true
+		caseDir := filepath.Join(dir, info.Name())
+
+		t.Run(info.Name(), func(t *testing.T) {
+			RunCase(t, caseDir, options, generators...)
+		})
+	}
+
+	if !found {
+		t.Fatalf("no test cases found in %s", dir)
+	}
+}
+
+// RunCase 运行 dir 中的一个用例
+func RunCase(t *testing.T, dir string, options *god.Options, generators ...string) {
+	t.Helper()
+
+	files, err := Generate(os.DirFS(dir), options, generators...)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if updating() {
+		err = Update(filepath.Join(dir, OUTPUT_DIR), files)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return
+	}
+
+	for _, diff := range Compare(filepath.Join(dir, OUTPUT_DIR), files) {
+		t.Error(diff)
+	}
+}
+
+// Generate 在内存中为 fsys 中 input 目录的包运行生成器，返回生成的文件（相对于 input 的文件名 -> 内容）
+func Generate(fsys fs.FS, options *god.Options, generators ...string) (map[string][]byte, error) {
+	o := god.Options{All: true}
+	if options != nil {
+		o = *options
+	}
+
+	pkg, err := god.LoadFS(fsys, INPUT_DIR, &o)
+
+	if err != nil {
+		return nil, fmt.Errorf("cannot load package: %w", err)
+	}
+
+	generated, err := god.Generate(pkg, generators...)
+
+	if err != nil {
+		return nil, err
+	}
+
+	w := god.NewMemoryWriter()
+
+	err = god.Write(w, generated)
+
+	if err != nil {
+		return nil, err
+	}
+
+	files := make(map[string][]byte)
+	for filename, content := range w.Files() {
+		files[strings.TrimPrefix(filename, INPUT_DIR+"/")] = content
+	}
+
+	return files, nil
+}
+
+// Compare 比较生成的文件与 dir 中的 golden 文件，返回每个不一致的文件的 diff
+func Compare(dir string, files map[string][]byte) []string {
+	golden, err := readGolden(dir)
+
+	if err != nil {
+		return []string{err.Error()}
+	}
+
+	names := make([]string, 0, len(files)+len(golden))
+	for name := range files {
+		names = append(names, name)
+	}
+	for name := range golden {
+		if _, ok := files[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	diffs := make([]string, 0)
+
+	for _, name := range names {
+		content, generated := files[name]
+		expected, existed := golden[name]
+
+		fromName, toName := path.Join("golden", name), path.Join("generated", name)
+		if !existed {
+			fromName = "/dev/null"
+		}
+		if !generated {
+			toName = "/dev/null"
+		}
+
+		if diff := utils.UnifiedDiff(fromName, toName, expected, content); diff != "" {
+			diffs = append(diffs, fmt.Sprintf("%s differs from golden file (run with -god.update to update):\n%s", name, diff))
+		}
+	}
+
+	return diffs
+}
+
+// Update 使用生成的文件替换 dir 中所有的 golden 文件
+func Update(dir string, files map[string][]byte) error {
+	err := os.RemoveAll(dir)
+
+	if err != nil {
+		return fmt.Errorf("cannot remove golden files in %s: %w", dir, err)
+	}
+
+	for name, content := range files {
+		filename := filepath.Join(dir, filepath.FromSlash(name))
+
+		err := os.MkdirAll(filepath.Dir(filename), 0755)
+
+		if err != nil {
+			return fmt.Errorf("cannot create directory for %s: %w", filename, err)
+		}
+
+		err = ioutil.WriteFile(filename, content, 0644)
+
+		if err != nil {
+			return fmt.Errorf("cannot save golden file %s: %w", filename, err)
+		}
+	}
+
+	return nil
+}
+
+// readGolden 读取 dir 中所有的 golden 文件，dir 不存在时没有 golden 文件
+func readGolden(dir string) (map[string][]byte, error) {
+	golden := make(map[string][]byte)
+
+	err := filepath.Walk(dir, func(filename string, info os.FileInfo, err error) error {
+		if os.IsNotExist(err) && filename == dir {
+			return filepath.SkipDir
+		}
+		if err != nil || info.IsDir() {
+			return err
+		}
+
+		content, err := ioutil.ReadFile(filename)
+
+		if err != nil {
+			return err
+		}
+
+		name, err := filepath.Rel(dir, filename)
+
+		if err != nil {
+			return err
+		}
+
+		golden[filepath.ToSlash(name)] = content
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("cannot read golden files in %s: %w", dir, err)
+	}
+
+	return golden, nil
+}
@@ -0,0 +1,143 @@
+package godtest
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+const userSource = "package model\n\ntype User struct {\n\tname string\n}\n"
+
+const userGetter = `// Code generated by god getter, DO NOT EDIT.
+
+package model
+
+func (u *User) Name() string {
+	return u.name
+}
+`
+
+func TestGenerate(t *testing.T) {
+	fsys := fstest.MapFS{INPUT_DIR + "/user.go": &fstest.MapFile{Data: []byte(userSource)}}
+
+	files, err := Generate(fsys, nil, "getter")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string][]byte{"user_getter.go": []byte(userGetter)}
+	if !reflect.DeepEqual(files, want) {
+		t.Errorf("Generate() = %q, want %q", files, want)
+	}
+}
+
+func TestCompare(t *testing.T) {
+	dir := t.TempDir()
+
+	err := Update(dir, map[string][]byte{
+		"same.go":    []byte("package model\n"),
+		"changed.go": []byte("package model\n\nvar a = 1\n"),
+		"removed.go": []byte("package model\n"),
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	diffs := Compare(dir, map[string][]byte{
+		"same.go":    []byte("package model\n"),
+		"changed.go": []byte("package model\n\nvar a = 2\n"),
+		"added.go":   []byte("package model\n"),
+	})
+
+	if len(diffs) != 3 {
+		t.Fatalf("Compare() returned %d diffs, want 3:\n%s", len(diffs), strings.Join(diffs, "\n"))
+	}
+
+	// 按照文件名排序
+	want := []string{
+		"added.go differs from golden file (run with -god.update to update):\n--- /dev/null\n+++ generated/added.go\n",
+		"changed.go differs from golden file (run with -god.update to update):\n--- golden/changed.go\n+++ generated/changed.go\n",
+		"removed.go differs from golden file (run with -god.update to update):\n--- golden/removed.go\n+++ /dev/null\n",
+	}
+	for i, prefix := range want {
+		if !strings.HasPrefix(diffs[i], prefix) {
+			t.Errorf("diff %d = %q, want prefix %q", i, diffs[i], prefix)
+		}
+	}
+
+	if !strings.Contains(diffs[1], "-var a = 1\n+var a = 2\n") {
+		t.Errorf("diff of changed.go does not contain the change:\n%s", diffs[1])
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), OUTPUT_DIR)
+
+	if err := Update(dir, map[string][]byte{"old.go": []byte("old")}); err != nil {
+		t.Fatal(err)
+	}
+
+	files := map[string][]byte{"user_getter.go": []byte("a"), "sub/user.go": []byte("b")}
+
+	if err := Update(dir, files); err != nil {
+		t.Fatal(err)
+	}
+
+	golden, err := readGolden(dir)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(golden, files) {
+		t.Errorf("golden files after Update = %q, want %q", golden, files)
+	}
+
+	if diffs := Compare(dir, files); len(diffs) != 0 {
+		t.Errorf("Compare() after Update returned diffs:\n%s", strings.Join(diffs, "\n"))
+	}
+}
+
+func TestReadGoldenMissingDir(t *testing.T) {
+	golden, err := readGolden(filepath.Join(t.TempDir(), "missing"))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(golden) != 0 {
+		t.Errorf("readGolden() = %q, want no files", golden)
+	}
+}
+
+func TestRun(t *testing.T) {
+	dir := t.TempDir()
+	caseDir := filepath.Join(dir, "user")
+
+	for name, content := range map[string]string{
+		filepath.Join(INPUT_DIR, "user.go"):         userSource,
+		filepath.Join(OUTPUT_DIR, "user_getter.go"): userGetter,
+	} {
+		filename := filepath.Join(caseDir, name)
+
+		if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(filename, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// 目录之外的文件不是用例
+	if err := ioutil.WriteFile(filepath.Join(dir, "README"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	Run(t, dir, nil, "getter")
+}
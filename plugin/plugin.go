@@ -0,0 +1,127 @@
+package plugin
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// EXECUTABLE_PREFIX 是外部生成器可执行文件名称的前缀
+const EXECUTABLE_PREFIX = "god-gen-"
+
+// Lookup 在 PATH 中查找名为 name 的外部生成器，返回可执行文件的路径
+func Lookup(name string) (string, error) {
+	return exec.LookPath(EXECUTABLE_PREFIX + name)
+}
+
+// List 返回 PATH 中所有的外部生成器，同名时与 Lookup 相同，使用 PATH 中靠前的
+func List() map[string]string {
+	plugins := make(map[string]string)
+
+	for _, dir := range filepath.SplitList(os.Getenv("PATH")) {
+		if dir == "" {
+			continue
+		}
+
+		infos, err := ioutil.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+
+		for _, info := range infos {
+			if info.IsDir() || !strings.HasPrefix(info.Name(), EXECUTABLE_PREFIX) {
+				continue
+			}
+			if runtime.GOOS != "windows" && info.Mode()&0111 == 0 {
+				continue
+			}
+
+			name := strings.TrimSuffix(strings.TrimPrefix(info.Name(), EXECUTABLE_PREFIX), filepath.Ext(info.Name()))
+			if _, ok := plugins[name]; !ok {
+				plugins[name] = filepath.Join(dir, info.Name())
+			}
+		}
+	}
+
+	return plugins
+}
+
+// Run 执行外部生成器，生成器的 stderr 会直接输出
+func Run(path string, request *Request) (*Response, error) {
+	input, err := json.Marshal(request)
+
+	if err != nil {
+		return nil, fmt.Errorf("cannot encode request: %w", err)
+	}
+
+	stdout := bytes.Buffer{}
+
+	cmd := exec.Command(path)
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Stdout = &stdout
+	cmd.Stderr = os.Stderr
+
+	err = cmd.Run()
+
+	if err != nil {
+		return nil, fmt.Errorf("cannot run %s: %w", path, err)
+	}
+
+	response := &Response{}
+
+	err = json.Unmarshal(stdout.Bytes(), response)
+
+	if err != nil {
+		return nil, fmt.Errorf("invalid response of %s: %w", path, err)
+	}
+
+	if response.Version != PROTOCOL_VERSION {
+		return nil, fmt.Errorf("unsupported protocol version %d of %s, expect %d", response.Version, path, PROTOCOL_VERSION)
+	}
+
+	if response.Error != "" {
+		return nil, fmt.Errorf("%s: %s", filepath.Base(path), response.Error)
+	}
+
+	return response, nil
+}
+
+// Serve 供使用 Go 编写的生成器使用：从 r 读取请求，调用 generate，将结果写入 w
+//
+//	func main() {
+//		err := plugin.Serve(os.Stdin, os.Stdout, generate)
+//		if err != nil {
+//			fmt.Fprintln(os.Stderr, err)
+//			os.Exit(1)
+//		}
+//	}
+//
+// generate 返回的错误会写入 Response 的 Error
+func Serve(r io.Reader, w io.Writer, generate func(request *Request) ([]*File, error)) error {
+	request := &Request{}
+
+	err := json.NewDecoder(r).Decode(request)
+
+	if err != nil {
+		return fmt.Errorf("cannot decode request: %w", err)
+	}
+
+	response := &Response{Version: PROTOCOL_VERSION}
+
+	if request.Version != PROTOCOL_VERSION {
+		response.Error = fmt.Sprintf("unsupported protocol version %d, expect %d", request.Version, PROTOCOL_VERSION)
+	} else if files, err := generate(request); err != nil {
+		response.Error = err.Error()
+	} else {
+		response.Files = files
+	}
+
+	return json.NewEncoder(w).Encode(response)
+}
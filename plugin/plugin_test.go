@@ -0,0 +1,166 @@
+package plugin_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/ImSingee/god/plugin"
+	"github.com/ImSingee/god/utils"
+	"os"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+// PLUGIN_MODE_ENV 不为空时测试程序本身作为外部生成器运行
+const PLUGIN_MODE_ENV = "GOD_PLUGIN_TEST_MODE"
+
+func TestMain(m *testing.M) {
+	switch os.Getenv(PLUGIN_MODE_ENV) {
+	case "":
+		os.Exit(m.Run())
+	case "serve":
+		err := plugin.Serve(os.Stdin, os.Stdout, generate)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+	case "future":
+		// 使用更新的协议版本返回结果
+		_ = json.NewEncoder(os.Stdout).Encode(&plugin.Response{Version: plugin.PROTOCOL_VERSION + 1})
+	}
+
+	os.Exit(0)
+}
+
+// generate 为每个结构体生成列出字段与 Getter 名称的注释，字段名为 fail 时返回错误
+func generate(request *plugin.Request) ([]*plugin.File, error) {
+	files := make([]*plugin.File, 0, len(request.Structs))
+
+	for _, s := range request.Structs {
+		b := strings.Builder{}
+		fmt.Fprintf(&b, "%spackage %s\n\n// %s %s:%d\n", request.Header, request.Package, s.Name, s.Pos.Filename, s.Pos.Line)
+
+		for _, field := range s.Fields {
+			if field.Name == "fail" {
+				return nil, fmt.Errorf("cannot generate for field %s", field.Name)
+			}
+
+			fmt.Fprintf(&b, "// %s %s\n", field.Name, field.GetterName)
+		}
+
+		files = append(files, &plugin.File{Struct: s.Name, Content: b.String()})
+	}
+
+	return files, nil
+}
+
+func newRequest(t *testing.T, src string) *plugin.Request {
+	t.Helper()
+
+	fsys := fstest.MapFS{"model/user.go": &fstest.MapFile{Data: []byte(src)}}
+
+	pkg, err := utils.LoadPackageFS(fsys, "model", &utils.Options{All: true, Header: "test header"})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return plugin.NewRequest("test", pkg, pkg.Structs.List())
+}
+
+func runPlugin(t *testing.T, mode string, request *plugin.Request) (*plugin.Response, error) {
+	t.Helper()
+
+	if err := os.Setenv(PLUGIN_MODE_ENV, mode); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Unsetenv(PLUGIN_MODE_ENV)
+
+	return plugin.Run(os.Args[0], request)
+}
+
+func TestRun(t *testing.T) {
+	request := newRequest(t, "package model\n\ntype User struct {\n\tname string\n\tuserId int\n}\n")
+
+	response, err := runPlugin(t, "serve", request)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if response.Version != plugin.PROTOCOL_VERSION {
+		t.Errorf("response version = %d, want %d", response.Version, plugin.PROTOCOL_VERSION)
+	}
+
+	if len(response.Files) != 1 {
+		t.Fatalf("got %d files, want 1", len(response.Files))
+	}
+
+	want := "// test header\n\npackage model\n\n// User user.go:3\n// name Name\n// userId UserID\n"
+	if file := response.Files[0]; file.Struct != "User" || file.Content != want {
+		t.Errorf("got file for %s:\n%s\nwant file for User:\n%s", file.Struct, file.Content, want)
+	}
+}
+
+func TestRunGenerateError(t *testing.T) {
+	request := newRequest(t, "package model\n\ntype User struct {\n\tfail string\n}\n")
+
+	_, err := runPlugin(t, "serve", request)
+
+	if err == nil || !strings.Contains(err.Error(), "cannot generate for field fail") {
+		t.Errorf("Run() error = %v, want the error returned by the generator", err)
+	}
+}
+
+func TestRunUnsupportedResponseVersion(t *testing.T) {
+	request := newRequest(t, "package model\n\ntype User struct {\n\tname string\n}\n")
+
+	_, err := runPlugin(t, "future", request)
+
+	if err == nil || !strings.Contains(err.Error(), fmt.Sprintf("unsupported protocol version %d", plugin.PROTOCOL_VERSION+1)) {
+		t.Errorf("Run() error = %v, want unsupported protocol version", err)
+	}
+}
+
+func TestServeUnsupportedRequestVersion(t *testing.T) {
+	input, err := json.Marshal(&plugin.Request{Version: plugin.PROTOCOL_VERSION + 1, Generator: "test"})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	called := false
+	output := &bytes.Buffer{}
+
+	err = plugin.Serve(bytes.NewReader(input), output, func(request *plugin.Request) ([]*plugin.File, error) {
+		called = true
+		return nil, nil
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if called {
+		t.Errorf("generate is called for an unsupported request version")
+	}
+
+	response := &plugin.Response{}
+
+	if err := json.Unmarshal(output.Bytes(), response); err != nil {
+		t.Fatal(err)
+	}
+
+	if response.Version != plugin.PROTOCOL_VERSION || !strings.Contains(response.Error, "unsupported protocol version") {
+		t.Errorf("Serve() response = %+v, want an unsupported protocol version error", response)
+	}
+}
+
+func TestServeInvalidRequest(t *testing.T) {
+	err := plugin.Serve(strings.NewReader("not json"), &bytes.Buffer{}, generate)
+
+	if err == nil {
+		t.Errorf("Serve() succeeded with an invalid request")
+	}
+}
@@ -0,0 +1,178 @@
+// Package plugin 定义了 god 与外部生成器之间的协议
+//
+// 外部生成器是 PATH 中名为 god-gen-<name> 的可执行文件，通过 god gen <name> 或 //god:<name> 注释使用。
+// god 将解析得到的包（结构体、字段、类型、tag、方法、位置等）编码为 JSON 的 Request 写入生成器的 stdin，
+// 生成器将 JSON 的 Response 写入 stdout，god 负责格式化与写入其中的文件
+//
+// 协议带有版本号，生成器收到不支持的版本时应当返回错误
+package plugin
+
+import (
+	"github.com/ImSingee/god/utils"
+	"go/token"
+	"path/filepath"
+	"sort"
+)
+
+// PROTOCOL_VERSION 是当前的协议版本，不兼容的修改会增加版本号
+const PROTOCOL_VERSION = 1
+
+// Request 是 god 发送给生成器的请求
+type Request struct {
+	Version   int                    `json:"version"`
+	Generator string                 `json:"generator"` // 生成器的名称，即 god-gen- 之后的部分
+	Package   string                 `json:"package"`   // 包名
+	Header    string                 `json:"header"`    // header 配置对应的注释，生成的文件应当以它开头
+	Options   map[string]interface{} `json:"options"`   // god 的所有配置，例如 naming
+	Structs   []*Struct              `json:"structs"`
+}
+
+// Struct 是一个结构体
+type Struct struct {
+	Name        string    `json:"name"`
+	ShortName   string    `json:"shortName"` // 推荐的 receiver 名称
+	Pos         Position  `json:"pos"`
+	Annotations []string  `json:"annotations"` // 类型定义上的注释指令（不含 //god: 前缀）
+	Imports     string    `json:"imports"`     // 结构体所在文件的导入语句
+	Fields      []*Field  `json:"fields"`      // 按照 sort 配置排序
+	Methods     []*Method `json:"methods"`     // 按照名称排序
+}
+
+// Field 是结构体的一个字段，包含 god 推导出的方法名与冲突检查的结果
+type Field struct {
+	Name     string   `json:"name"`
+	Type     string   `json:"type"`
+	Tag      string   `json:"tag"` // 原始的 struct tag（不含反引号）
+	BaseName string   `json:"baseName"`
+	Pos      Position `json:"pos"`
+
+	IsPublic bool `json:"isPublic"`
+	Ignored  bool `json:"ignored"` // 字段名无效，不应当为它生成代码
+	Skip     bool `json:"skip"`    // 通过 //god:skip 跳过
+
+	GetterName    string      `json:"getterName"`
+	GetterSkipped *Diagnostic `json:"getterSkipped,omitempty"` // 为空表示会生成 Getter
+	SetterName    string      `json:"setterName"`
+	SetterSkipped *Diagnostic `json:"setterSkipped,omitempty"` // 为空表示会生成 Setter
+}
+
+// Method 是结构体已经定义的方法
+type Method struct {
+	Name string   `json:"name"`
+	Pos  Position `json:"pos"`
+}
+
+// Diagnostic 是方法不会生成的原因
+type Diagnostic struct {
+	Reason  string `json:"reason"`
+	Message string `json:"message"`
+}
+
+// Position 是源码中的位置，文件名相对于包所在的目录
+type Position struct {
+	Filename string `json:"filename"`
+	Line     int    `json:"line"`
+	Column   int    `json:"column"`
+}
+
+// Response 是生成器返回的结果
+type Response struct {
+	Version int     `json:"version"`
+	Files   []*File `json:"files"`
+	Error   string  `json:"error,omitempty"` // 不为空时表示生成失败
+}
+
+// File 是为一个结构体生成的代码，文件名由 god 的 filename 配置决定
+type File struct {
+	Struct  string `json:"struct"`
+	Content string `json:"content"`
+}
+
+// NewRequest 将 god 解析得到的包中的结构体转换为请求
+func NewRequest(generator string, pkg *utils.Package, structs []*utils.Struct) *Request {
+	request := &Request{
+		Version:   PROTOCOL_VERSION,
+		Generator: generator,
+		Package:   pkg.Name,
+		Header:    pkg.Options.HeaderComment(),
+		Options:   pkg.Options.AllSettings(),
+		Structs:   make([]*Struct, 0, len(structs)),
+	}
+
+	for _, s := range structs {
+		request.Structs = append(request.Structs, newStruct(pkg.Dir, s))
+	}
+
+	return request
+}
+
+// newStruct 转换结构体，位置中的文件名相对于包所在的目录 dir
+func newStruct(dir string, s *utils.Struct) *Struct {
+	result := &Struct{
+		Name:        s.Name,
+		ShortName:   s.ShortName,
+		Pos:         newPosition(dir, s.Pos),
+		Annotations: make([]string, 0, len(s.Annotations)),
+		Imports:     s.ImportedStatements,
+		Fields:      make([]*Field, 0, len(s.FieldList)),
+		Methods:     make([]*Method, 0, len(s.Methods)),
+	}
+
+	for _, annotation := range s.Annotations {
+		result.Annotations = append(result.Annotations, annotation.Name)
+	}
+
+	for _, field := range s.FieldList {
+		result.Fields = append(result.Fields, &Field{
+			Name:          field.Name,
+			Type:          field.Type,
+			Tag:           field.Tag,
+			BaseName:      field.BaseName,
+			Pos:           newPosition(dir, field.Pos),
+			IsPublic:      field.IsPublic,
+			Ignored:       field.ShouldIgnore,
+			Skip:          field.Skip,
+			GetterName:    field.GetterName,
+			GetterSkipped: newDiagnostic(field.GetterSkipped),
+			SetterName:    field.SetterName,
+			SetterSkipped: newDiagnostic(field.SetterSkipped),
+		})
+	}
+
+	for _, method := range s.Methods {
+		result.Methods = append(result.Methods, &Method{
+			Name: method.Name,
+			Pos:  newPosition(dir, method.Pos),
+		})
+	}
+	sort.Slice(result.Methods, func(i, j int) bool {
+		return result.Methods[i].Name < result.Methods[j].Name
+	})
+
+	return result
+}
+
+func newDiagnostic(d *utils.Diagnostic) *Diagnostic {
+	if d == nil {
+		return nil
+	}
+
+	return &Diagnostic{
+		Reason:  string(d.Reason),
+		Message: d.Message,
+	}
+}
+
+// newPosition 转换位置，无法得到相对于 dir 的文件名时保持不变
+func newPosition(dir string, pos token.Position) Position {
+	filename := pos.Filename
+	if rel, err := filepath.Rel(dir, filename); err == nil {
+		filename = rel
+	}
+
+	return Position{
+		Filename: filename,
+		Line:     pos.Line,
+		Column:   pos.Column,
+	}
+}
@@ -0,0 +1,54 @@
+package utils
+
+import (
+	"go/ast"
+	"go/token"
+	"strings"
+)
+
+// ANNOTATION_PREFIX 是注释指令的前缀，例如类型定义上的 //god:getter 与字段上的 //god:skip
+//
+// 与 //go:generate 一样，前缀中不能有空格
+const ANNOTATION_PREFIX = "//god:"
+
+// ANNOTATION_SKIP 标记的字段不会生成任何方法
+const ANNOTATION_SKIP = "skip"
+
+// Annotation 是一条注释指令
+type Annotation struct {
+	Name string // 指令名称，类型上为生成器名称
+	Pos  token.Position
+}
+
+func (a *Annotation) String() string {
+	return ANNOTATION_PREFIX + a.Name
+}
+
+// getAnnotations 返回注释中的所有指令，指令名称之后的内容会被忽略
+func (p *Package) getAnnotations(groups ...*ast.CommentGroup) []*Annotation {
+	annotations := make([]*Annotation, 0)
+
+	for _, group := range groups {
+		if group == nil {
+			continue
+		}
+
+		for _, comment := range group.List {
+			if !strings.HasPrefix(comment.Text, ANNOTATION_PREFIX) {
+				continue
+			}
+
+			words := strings.Fields(comment.Text[len(ANNOTATION_PREFIX):])
+			if len(words) == 0 {
+				continue
+			}
+
+			annotations = append(annotations, &Annotation{
+				Name: words[0],
+				Pos:  p.position(comment.Pos()),
+			})
+		}
+	}
+
+	return annotations
+}
@@ -0,0 +1,86 @@
+package utils
+
+import (
+	"sort"
+	"strings"
+)
+
+// DEFAULT_BUILDER_PREFIX 是 Builder 方法名默认的前缀
+const DEFAULT_BUILDER_PREFIX = "With"
+
+// BuilderTypeName 返回结构体的 Builder 类型名称，例如 UserBuilder
+func (s *Struct) BuilderTypeName() string {
+	return s.Name + "Builder"
+}
+
+// BuilderConstructorName 返回创建 Builder 的函数名称，例如 NewUserBuilder
+func (s *Struct) BuilderConstructorName() string {
+	return "New" + s.BuilderTypeName()
+}
+
+// deriveBuilderMethods 推导 Builder 中设置每个字段的方法名（前缀加上推导出的字段名），并检查已经存在的声明：
+//
+// 包中已经定义了 XBuilder 或 NewXBuilder 时不生成 Builder，XBuilder 上已经定义的方法不会重复生成，
+// 多个字段推导出相同的方法名（例如 field3 与 Field3）时这些方法都不生成并给出警告
+func (p *Package) deriveBuilderMethods(s *Struct) {
+	prefix := p.Options.BuilderMethodPrefix()
+
+	for _, name := range []string{s.BuilderTypeName(), s.BuilderConstructorName()} {
+		if decl, ok := p.decls[name]; ok {
+			s.BuilderSkipped = newDiagnostic(s.Pos, REASON_METHOD_EXISTS, "%s already exists at %s", name, decl.Pos)
+			break
+		}
+	}
+
+	methods := p.methodsOf(s.BuilderTypeName())
+	_, s.BuildExists = methods["Build"]
+
+	owners := make(map[string][]*Field)
+	var names []string
+
+	for _, field := range s.FieldList {
+		switch {
+		case field.ShouldIgnore:
+			field.skipBuilder(s, REASON_INVALID_NAME, "name is invalid")
+			continue
+		case field.Skip:
+			field.skipBuilder(s, REASON_ANNOTATION_SKIP, "field is skipped by annotation")
+			continue
+		}
+
+		field.BuilderName = prefix + ToPascalName(field.BaseName, p.Options.Initialisms...)
+		field.WillGenerateBuilder = true
+
+		if s.BuilderSkipped != nil {
+			field.skipBuilder(s, s.BuilderSkipped.Reason, "%s", s.BuilderSkipped.Message)
+			continue
+		}
+
+		if function, ok := methods[field.BuilderName]; ok {
+			field.skipBuilder(s, REASON_METHOD_EXISTS, "method %s already exists at %s", field.BuilderName, function.Pos)
+			continue
+		}
+
+		if _, ok := owners[field.BuilderName]; !ok {
+			names = append(names, field.BuilderName)
+		}
+		owners[field.BuilderName] = append(owners[field.BuilderName], field)
+	}
+
+	for _, name := range names {
+		fields := owners[name]
+		if len(fields) < 2 {
+			continue
+		}
+
+		fieldNames := make([]string, 0, len(fields))
+		for _, field := range fields {
+			fieldNames = append(fieldNames, field.Name)
+		}
+		sort.Strings(fieldNames)
+
+		for _, field := range fields {
+			field.skipBuilder(s, REASON_NAME_CONFLICT, "builder method %s is derived from multiple fields: %s", name, strings.Join(fieldNames, ", "))
+		}
+	}
+}
@@ -0,0 +1,135 @@
+package utils
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// CacheEntry 记录一次生成的输入与输出，输入没有变化且输出文件没有被修改时可以跳过生成
+type CacheEntry struct {
+	Inputs  string            `json:"inputs"`  // 所有输入的 hash
+	Outputs map[string]string `json:"outputs"` // 输出文件的绝对路径 -> 内容的 hash
+
+	path string // 缓存文件的路径
+}
+
+// HashBytes 返回内容的 sha256
+func HashBytes(content []byte) string {
+	sum := sha256.Sum256(content)
+
+	return hex.EncodeToString(sum[:])
+}
+
+// IsGodGeneratedSource 判断源码是否由 god 生成，只检查 package 语句之前的注释
+func IsGodGeneratedSource(src []byte) bool {
+	for _, line := range strings.Split(ToString(src), "\n") {
+		line = strings.TrimSpace(line)
+
+		if strings.HasPrefix(line, "package ") {
+			return false
+		}
+		if GENERATED_BY_GOD.MatchString(strings.TrimSpace(strings.TrimPrefix(line, "//"))) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// HashInputs 计算生成的输入的 hash：版本、额外的输入以及文件内容（由 god 生成的文件会被忽略）
+func HashInputs(filenames []string, extras ...string) (string, error) {
+	h := sha256.New()
+
+	_, _ = fmt.Fprintf(h, "god %s\x00", VERSION)
+
+	for _, extra := range extras {
+		_, _ = fmt.Fprintf(h, "%s\x00", extra)
+	}
+
+	for _, filename := range filenames {
+		src, err := ioutil.ReadFile(filename)
+
+		if err != nil {
+			return "", fmt.Errorf("cannot read file %s: %w", filename, err)
+		}
+
+		if IsGodGeneratedSource(src) {
+			continue
+		}
+
+		_, _ = fmt.Fprintf(h, "%s\x00%d\x00", filename, len(src))
+		_, _ = h.Write(src)
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// LoadCacheEntry 读取 identity 对应的缓存记录，不存在时返回空的记录
+func LoadCacheEntry(dir, identity string) (*CacheEntry, error) {
+	entry := &CacheEntry{
+		Outputs: make(map[string]string),
+		path:    filepath.Join(dir, HashBytes([]byte(identity))+".json"),
+	}
+
+	content, err := ioutil.ReadFile(entry.path)
+
+	if os.IsNotExist(err) {
+		return entry, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("cannot read cache %s: %w", entry.path, err)
+	}
+
+	old := &CacheEntry{}
+	if err := json.Unmarshal(content, old); err != nil {
+		// 损坏的缓存等同于没有缓存
+		return entry, nil
+	}
+
+	entry.Inputs = old.Inputs
+	if old.Outputs != nil {
+		entry.Outputs = old.Outputs
+	}
+
+	return entry, nil
+}
+
+// UpToDate 判断输入是否与记录相同，并且所有输出文件都没有被修改
+func (entry *CacheEntry) UpToDate(inputs string) bool {
+	if entry.Inputs == "" || entry.Inputs != inputs {
+		return false
+	}
+
+	for filename, hash := range entry.Outputs {
+		content, err := ioutil.ReadFile(filename)
+
+		if err != nil || HashBytes(content) != hash {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Save 写入缓存记录
+func (entry *CacheEntry) Save() error {
+	content, err := json.Marshal(entry)
+
+	if err != nil {
+		return err
+	}
+
+	err = os.MkdirAll(filepath.Dir(entry.path), 0755)
+
+	if err != nil {
+		return fmt.Errorf("cannot create cache directory: %w", err)
+	}
+
+	return ioutil.WriteFile(entry.path, content, 0644)
+}
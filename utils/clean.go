@@ -0,0 +1,219 @@
+package utils
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// StaleFile 是一个不再需要的生成文件
+type StaleFile struct {
+	Filename string
+	Reason   string
+}
+
+// GetReceiverTypeName 返回方法接收者的类型名称，例如 *SomeStruct 返回 SomeStruct
+func GetReceiverTypeName(expr ast.Expr) string {
+	for {
+		switch e := expr.(type) {
+		case *ast.StarExpr:
+			expr = e.X
+		case *ast.ParenExpr:
+			expr = e.X
+		case *ast.IndexExpr:
+			expr = e.X
+		case *ast.Ident:
+			return e.Name
+		default:
+			return ""
+		}
+	}
+}
+
+// FindStaleGeneratedFiles 找到目录中由 god 生成、但源结构体或生成器已经不存在的文件
+//
+// isGenerator 用于判断生成器是否存在，合并生成的文件（没有生成器名称）只检查结构体
+func FindStaleGeneratedFiles(dir string, isGenerator func(name string) bool) ([]*StaleFile, error) {
+	filenames, err := filepath.Glob(filepath.Join(dir, "*.go"))
+
+	if err != nil {
+		return nil, fmt.Errorf("cannot list go files in %s: %w", dir, err)
+	}
+
+	fset := token.NewFileSet()
+	typeNames := make(map[string]bool) // 源码（不含生成的文件）中定义的类型
+	generated := make(map[string]*ast.File)
+	generators := make(map[string]string)
+
+	for _, filename := range filenames {
+		f, err := parser.ParseFile(fset, filename, nil, parser.ParseComments)
+
+		if err != nil {
+			return nil, fmt.Errorf("cannot parse file %s: %w", filename, err)
+		}
+
+		if generator, ok := GetGodGenerator(f); ok {
+			generated[filename] = f
+			generators[filename] = generator
+
+			continue
+		}
+
+		for _, decl := range f.Decls {
+			genDecl, ok := decl.(*ast.GenDecl)
+			if !ok || genDecl.Tok != token.TYPE {
+				continue
+			}
+
+			for _, spec := range genDecl.Specs {
+				if typeSpec, ok := spec.(*ast.TypeSpec); ok {
+					typeNames[typeSpec.Name.Name] = true
+				}
+			}
+		}
+	}
+
+	stale := make([]*StaleFile, 0)
+
+	for filename, f := range generated {
+		generator := generators[filename]
+
+		if generator != "" && !isGenerator(generator) {
+			stale = append(stale, &StaleFile{
+				Filename: filename,
+				Reason:   fmt.Sprintf("generator %s no longer exists", generator),
+			})
+
+			continue
+		}
+
+		// 生成文件自身定义的类型（例如 Builder）不需要在源码中存在，但是它引用的类型（例如 UserBuilder 的 target User）需要
+		local := make(map[string][]string)
+		for _, decl := range f.Decls {
+			if genDecl, ok := decl.(*ast.GenDecl); ok && genDecl.Tok == token.TYPE {
+				for _, spec := range genDecl.Specs {
+					if typeSpec, ok := spec.(*ast.TypeSpec); ok {
+						local[typeSpec.Name.Name] = referencedTypes(typeSpec.Type)
+					}
+				}
+			}
+		}
+
+		missing := make(map[string]bool)
+		check := func(name string) {
+			if name != "" && !typeNames[name] {
+				if _, ok := local[name]; !ok {
+					missing[name] = true
+				}
+			}
+		}
+
+		for _, references := range local {
+			for _, name := range references {
+				check(name)
+			}
+		}
+
+		for _, decl := range f.Decls {
+			funcDecl, ok := decl.(*ast.FuncDecl)
+			if !ok || funcDecl.Recv == nil || len(funcDecl.Recv.List) == 0 {
+				continue
+			}
+
+			check(GetReceiverTypeName(funcDecl.Recv.List[0].Type))
+		}
+
+		if len(missing) != 0 {
+			names := make([]string, 0, len(missing))
+			for name := range missing {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+
+			stale = append(stale, &StaleFile{
+				Filename: filename,
+				Reason:   fmt.Sprintf("struct %s no longer exists", strings.Join(names, ", ")),
+			})
+		}
+	}
+
+	sort.Slice(stale, func(i, j int) bool {
+		return stale[i].Filename < stale[j].Filename
+	})
+
+	return stale, nil
+}
+
+// referencedTypes 返回类型表达式中引用的当前包中的类型名称，不含内置类型、其他包中的类型与字段名
+func referencedTypes(expr ast.Expr) []string {
+	names := make([]string, 0)
+
+	var inspect func(node ast.Node) bool
+	inspect = func(node ast.Node) bool {
+		switch node := node.(type) {
+		case *ast.SelectorExpr:
+			return false
+		case *ast.Field:
+			ast.Inspect(node.Type, inspect)
+			return false
+		case *ast.Ident:
+			if types.Universe.Lookup(node.Name) == nil {
+				names = append(names, node.Name)
+			}
+		}
+
+		return true
+	}
+
+	ast.Inspect(expr, inspect)
+
+	return names
+}
+
+// FindReplacedGeneratedFiles 找到 dir 中由 god 生成、并且定义了 src 中的函数的其他文件，即被 filename 替代的文件
+//
+// 例如改为单文件输出之后，之前为每个结构体生成的文件
+func FindReplacedGeneratedFiles(dir, filename string, src []byte) ([]string, error) {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, filename, src, 0)
+
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse file %s: %w", filename, err)
+	}
+
+	declared := make(map[string]bool)
+	for _, fn := range declaredFuncs(f) {
+		declared[fn] = true
+	}
+
+	filenames, err := filepath.Glob(filepath.Join(dir, "*.go"))
+
+	if err != nil {
+		return nil, fmt.Errorf("cannot list go files in %s: %w", dir, err)
+	}
+
+	replaced := make([]string, 0)
+
+	for _, name := range filenames {
+		if filepath.Base(name) == filepath.Base(filename) {
+			continue
+		}
+
+		f, err := parser.ParseFile(fset, name, nil, parser.ParseComments)
+
+		if err != nil {
+			return nil, fmt.Errorf("cannot parse file %s: %w", name, err)
+		}
+
+		if IsGeneratedByGod(f) && declaresAny(f, declared) {
+			replaced = append(replaced, name)
+		}
+	}
+
+	return replaced, nil
+}
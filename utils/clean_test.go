@@ -0,0 +1,121 @@
+package utils
+
+import (
+	"go/parser"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestFindStaleGeneratedFiles(t *testing.T) {
+	dir := t.TempDir()
+
+	files := map[string]string{
+		"order.go": "package model\n\ntype Order struct {\n\tid int\n}\n",
+		"order_builder.go": `// Code generated by god builder, DO NOT EDIT.
+
+package model
+
+type OrderBuilder struct {
+	target Order
+}
+
+func NewOrderBuilder() *OrderBuilder {
+	return &OrderBuilder{}
+}
+
+func (b *OrderBuilder) WithID(id int) *OrderBuilder {
+	b.target.id = id
+	return b
+}
+`,
+		"user_getter.go": `// Code generated by god getter, DO NOT EDIT.
+
+package model
+
+func (u *User) Name() string {
+	return u.name
+}
+`,
+		"user_builder.go": `// Code generated by god builder, DO NOT EDIT.
+
+package model
+
+import "time"
+
+type UserBuilder struct {
+	target User
+	at     time.Time
+}
+
+func NewUserBuilder() *UserBuilder {
+	return &UserBuilder{}
+}
+
+func (b *UserBuilder) Build() *User {
+	result := b.target
+	return &result
+}
+`,
+		"order_removed.go": `// Code generated by god removed, DO NOT EDIT.
+
+package model
+
+func (o *Order) Removed() {}
+`,
+	}
+
+	for name, content := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	stale, err := FindStaleGeneratedFiles(dir, func(name string) bool {
+		return name != "removed"
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := make(map[string]string)
+	for _, file := range stale {
+		got[filepath.Base(file.Filename)] = file.Reason
+	}
+
+	want := map[string]string{
+		"order_removed.go": "generator removed no longer exists",
+		"user_builder.go":  "struct User no longer exists",
+		"user_getter.go":   "struct User no longer exists",
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("FindStaleGeneratedFiles() = %v, want %v", got, want)
+	}
+}
+
+func TestReferencedTypes(t *testing.T) {
+	cases := []struct {
+		expr string
+		want []string
+	}{
+		{expr: "struct{ target User }", want: []string{"User"}},
+		{expr: "struct{ id int; at time.Time }", want: []string{}},
+		{expr: "map[Key][]*Value", want: []string{"Key", "Value"}},
+		{expr: "func(user User) error", want: []string{"User"}},
+	}
+
+	for _, c := range cases {
+		expr, err := parser.ParseExpr(c.expr)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if got := referencedTypes(expr); !reflect.DeepEqual(got, c.want) {
+			t.Errorf("referencedTypes(%s) = %q, want %q", c.expr, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,174 @@
+package utils
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/spf13/cast"
+	"github.com/spf13/viper"
+	"os"
+	"path/filepath"
+)
+
+// CONFIG_FILENAME 是项目配置文件的名称，从工作目录开始逐级向上查找
+//
+// 配置项与命令行参数同名，命令行参数优先于配置文件，例如：
+//
+//	naming: java
+//	filename: "{{ $.struct.LowerName }}_{{ $.type }}.go"
+//	generators: [getter, setter]
+//	header: Copyright 2020 Someone
+//	templates: tools/god
+//	include: ["*Model"]
+//	exclude: ["internal*"]
+//	packages:
+//	  - match: "internal/**"
+//	    naming: go
+//
+// packages 中的配置只对匹配的包（相对于配置文件所在目录的路径）生效
+const CONFIG_FILENAME = ".god.yaml"
+
+// configPathKeys 中的配置是路径，配置文件中的相对路径相对于配置文件所在的目录
+var configPathKeys = []string{"templates"}
+
+// FindConfigFile 从 dir 开始逐级向上查找配置文件，找不到时返回空字符串
+func FindConfigFile(dir string) (string, error) {
+	dir, err := filepath.Abs(dir)
+
+	if err != nil {
+		return "", err
+	}
+
+	for {
+		filename := filepath.Join(dir, CONFIG_FILENAME)
+
+		if info, err := os.Stat(filename); err == nil && !info.IsDir() {
+			return filename, nil
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", nil
+		}
+		dir = parent
+	}
+}
+
+// LoadConfig 为 dir 中的包加载配置文件，filename 为空时自动查找，返回实际使用的配置文件
+//
+// 没有配置文件时会清空之前加载的配置
+func LoadConfig(dir, filename string) (string, error) {
+	var err error
+
+	if filename == "" {
+		filename, err = FindConfigFile(dir)
+
+		if err != nil {
+			return "", err
+		}
+	}
+
+	if filename == "" {
+		viper.SetConfigType("yaml")
+
+		return "", viper.ReadConfig(bytes.NewReader(nil))
+	}
+
+	// 配置文件中的路径与 packages 的匹配都相对于配置文件所在的目录
+	filename, err = filepath.Abs(filename)
+
+	if err != nil {
+		return "", err
+	}
+
+	viper.SetConfigFile(filename)
+
+	err = viper.ReadInConfig()
+
+	if err != nil {
+		return "", fmt.Errorf("cannot read config file %s: %w", filename, err)
+	}
+
+	absDir, err := filepath.Abs(dir)
+
+	if err != nil {
+		return "", err
+	}
+
+	rel, err := filepath.Rel(filepath.Dir(filename), absDir)
+
+	if err != nil {
+		return "", err
+	}
+
+	rel = filepath.ToSlash(rel)
+
+	// 单独读取一次配置文件，得到配置文件中（而不是命令行参数中）的路径
+	config := viper.New()
+	config.SetConfigFile(filename)
+
+	err = config.ReadInConfig()
+
+	if err != nil {
+		return "", fmt.Errorf("cannot read config file %s: %w", filename, err)
+	}
+
+	paths := make(map[string]interface{})
+	for _, key := range configPathKeys {
+		if config.IsSet(key) {
+			paths[key] = config.GetString(key)
+		}
+	}
+
+	packages, ok := viper.Get("packages").([]interface{})
+	if viper.IsSet("packages") && !ok {
+		return "", fmt.Errorf("invalid config file %s: packages must be a list", filename)
+	}
+
+	for i, item := range packages {
+		override, err := cast.ToStringMapE(item)
+
+		if err != nil {
+			return "", fmt.Errorf("invalid config file %s: packages[%d] must be a map", filename, i)
+		}
+
+		match := cast.ToString(override["match"])
+		if match == "" {
+			return "", fmt.Errorf("invalid config file %s: packages[%d] must have a match", filename, i)
+		}
+
+		if !MatchGlob(match, rel) {
+			continue
+		}
+
+		delete(override, "match")
+
+		err = viper.MergeConfigMap(override)
+
+		if err != nil {
+			return "", fmt.Errorf("cannot apply packages[%d] of config file %s: %w", i, filename, err)
+		}
+
+		for _, key := range configPathKeys {
+			if value, ok := override[key]; ok {
+				paths[key] = cast.ToString(value)
+			}
+		}
+	}
+
+	for key, value := range paths {
+		path := value.(string)
+		if path != "" && !filepath.IsAbs(path) {
+			paths[key] = filepath.Join(filepath.Dir(filename), path)
+		}
+	}
+
+	if len(paths) != 0 {
+		err = viper.MergeConfigMap(paths)
+
+		if err != nil {
+			return "", fmt.Errorf("cannot apply config file %s: %w", filename, err)
+		}
+	}
+
+	return filename, nil
+}
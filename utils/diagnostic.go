@@ -0,0 +1,140 @@
+package utils
+
+import (
+	"fmt"
+	"go/token"
+	"sort"
+)
+
+// Reason 是方法未生成的原因代码
+type Reason string
+
+const (
+	REASON_INVALID_NAME    Reason = "invalid-name"    // 字段名无效（例如 _ 或非 ASCII 开头）
+	REASON_PUBLIC_FIELD    Reason = "public-field"    // public field 不生成 Getter
+	REASON_TAG_DISABLED    Reason = "tag-disabled"    // 通过 tag 禁用
+	REASON_ANNOTATION_SKIP Reason = "annotation-skip" // 通过 //god:skip 注释跳过
+	REASON_METHOD_EXISTS   Reason = "method-exists"   // 已经存在自定义的同名方法
+	REASON_SAME_NAME_FIELD Reason = "same-name-field" // 存在只有首字母大小写不同的同名字段，例如 field3 与 Field3
+	REASON_FIELD_CONFLICT  Reason = "field-conflict"  // 方法名与其他字段名相同
+	REASON_NAME_CONFLICT   Reason = "name-conflict"   // 多个字段推导出了相同的方法名
+	REASON_METHOD_CONFLICT Reason = "method-conflict" // 已经存在只有大小写不同、或者与不处理缩写词时的名称相同的方法，例如 Url 与 URL
+)
+
+// IsWarning 表示该原因是否需要给出警告，其余的原因属于预期内的行为
+func (r Reason) IsWarning() bool {
+	switch r {
+	case REASON_SAME_NAME_FIELD, REASON_FIELD_CONFLICT, REASON_NAME_CONFLICT, REASON_METHOD_CONFLICT:
+		return true
+	default:
+		return false
+	}
+}
+
+// Diagnostic 记录一条诊断信息及其在源码中的位置
+type Diagnostic struct {
+	Pos     token.Position
+	Reason  Reason
+	Message string
+}
+
+func (d *Diagnostic) String() string {
+	return fmt.Sprintf("%s: %s [%s]", d.Pos, d.Message, d.Reason)
+}
+
+func newDiagnostic(pos token.Position, reason Reason, format string, args ...interface{}) *Diagnostic {
+	return &Diagnostic{
+		Pos:     pos,
+		Reason:  reason,
+		Message: fmt.Sprintf(format, args...),
+	}
+}
+
+// Diagnostics 按照源码位置排序
+type Diagnostics []*Diagnostic
+
+func (ds Diagnostics) Sort() {
+	sort.SliceStable(ds, func(i, j int) bool {
+		a, b := ds[i].Pos, ds[j].Pos
+
+		if a.Filename != b.Filename {
+			return a.Filename < b.Filename
+		}
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		if a.Column != b.Column {
+			return a.Column < b.Column
+		}
+		return ds[i].Message < ds[j].Message
+	})
+}
+
+// Warnings 返回所有结构体中的警告，按照源码位置排序
+func (structs Structs) Warnings() Diagnostics {
+	var warnings Diagnostics
+
+	for _, s := range structs {
+		warnings = append(warnings, s.Warnings...)
+	}
+
+	warnings.Sort()
+
+	return warnings
+}
+
+// BuilderWarnings 返回所有结构体中 Builder 的警告，按照源码位置排序
+func (structs Structs) BuilderWarnings() Diagnostics {
+	var warnings Diagnostics
+
+	for _, s := range structs {
+		warnings = append(warnings, s.BuilderWarnings...)
+	}
+
+	warnings.Sort()
+
+	return warnings
+}
+
+// skipGetter 记录 Getter 不会生成的原因，只保留第一个原因
+func (f *Field) skipGetter(s *Struct, reason Reason, format string, args ...interface{}) {
+	f.WillGenerateGetter = false
+
+	if f.GetterSkipped == nil {
+		f.GetterSkipped = newDiagnostic(f.Pos, reason, format, args...)
+		f.IgnoreReason = f.GetterSkipped.Message
+
+		if s != nil && reason.IsWarning() {
+			s.Warnings = append(s.Warnings, f.GetterSkipped)
+		}
+	}
+}
+
+// skipSetter 记录 Setter 不会生成的原因，只保留第一个原因
+func (f *Field) skipSetter(s *Struct, reason Reason, format string, args ...interface{}) {
+	f.WillGenerateSetter = false
+
+	if f.SetterSkipped == nil {
+		f.SetterSkipped = newDiagnostic(f.Pos, reason, format, args...)
+		if f.IgnoreReason == "" {
+			f.IgnoreReason = f.SetterSkipped.Message
+		}
+
+		if s != nil && reason.IsWarning() {
+			s.Warnings = append(s.Warnings, f.SetterSkipped)
+		}
+	}
+}
+
+// skipBuilder 记录 Builder 中不会生成设置该字段的方法的原因，只保留第一个原因
+func (f *Field) skipBuilder(s *Struct, reason Reason, format string, args ...interface{}) {
+	f.WillGenerateBuilder = false
+
+	if f.BuilderSkipped == nil {
+		f.BuilderSkipped = newDiagnostic(f.Pos, reason, format, args...)
+
+		if s != nil && reason.IsWarning() {
+			s.BuilderWarnings = append(s.BuilderWarnings, f.BuilderSkipped)
+		}
+	}
+}
@@ -0,0 +1,199 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DIFF_CONTEXT 是 unified diff 中每个改动前后保留的行数
+const DIFF_CONTEXT = 3
+
+type diffEdit struct {
+	op   byte // ' ' 不变，'-' 删除，'+' 新增
+	text string
+}
+
+// UnifiedDiff 返回从 a 到 b 的 unified diff，两者相同时返回空字符串
+func UnifiedDiff(fromName, toName string, a, b []byte) string {
+	edits := diffLines(splitLines(ToString(a)), splitLines(ToString(b)))
+
+	// 每个 edit 之前 a 与 b 已经经过的行数
+	aLines := make([]int, len(edits)+1)
+	bLines := make([]int, len(edits)+1)
+	changed := false
+	for i, e := range edits {
+		aLines[i+1], bLines[i+1] = aLines[i], bLines[i]
+		if e.op != '+' {
+			aLines[i+1]++
+		}
+		if e.op != '-' {
+			bLines[i+1]++
+		}
+		if e.op != ' ' {
+			changed = true
+		}
+	}
+
+	if !changed {
+		return ""
+	}
+
+	out := strings.Builder{}
+	fmt.Fprintf(&out, "--- %s\n+++ %s\n", fromName, toName)
+
+	for i := 0; i < len(edits); {
+		if edits[i].op == ' ' {
+			i++
+			continue
+		}
+
+		// 找到本 hunk 中最后一个改动：两个改动之间的不变行不超过 2*DIFF_CONTEXT 时合并为一个 hunk
+		last := i
+		for j := i; j < len(edits); {
+			if edits[j].op != ' ' {
+				last = j
+				j++
+				continue
+			}
+
+			k := j
+			for k < len(edits) && edits[k].op == ' ' {
+				k++
+			}
+			if k == len(edits) || k-j > 2*DIFF_CONTEXT {
+				break
+			}
+			j = k
+		}
+
+		start := i - DIFF_CONTEXT
+		if start < 0 {
+			start = 0
+		}
+		end := last + 1 + DIFF_CONTEXT
+		if end > len(edits) {
+			end = len(edits)
+		}
+
+		fmt.Fprintf(&out, "@@ -%s +%s @@\n",
+			hunkRange(aLines[start], aLines[end]-aLines[start]),
+			hunkRange(bLines[start], bLines[end]-bLines[start]),
+		)
+
+		for _, e := range edits[start:end] {
+			out.WriteByte(e.op)
+			out.WriteString(e.text)
+			if !strings.HasSuffix(e.text, "\n") {
+				out.WriteString("\n\\ No newline at end of file\n")
+			}
+		}
+
+		i = end
+	}
+
+	return out.String()
+}
+
+func hunkRange(start, count int) string {
+	if count == 0 {
+		return fmt.Sprintf("%d,0", start)
+	}
+	if count == 1 {
+		return fmt.Sprintf("%d", start+1)
+	}
+
+	return fmt.Sprintf("%d,%d", start+1, count)
+}
+
+// splitLines 按行拆分文本，每行保留末尾的换行符
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+
+	lines := strings.SplitAfter(s, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	return lines
+}
+
+// diffLines 使用 Myers 算法计算最短编辑序列
+func diffLines(a, b []string) []diffEdit {
+	n, m := len(a), len(b)
+	max := n + m
+	v := make([]int, 2*max+2)
+	var trace [][]int
+
+	for d := 0; d <= max; d++ {
+		snapshot := make([]int, len(v))
+		copy(snapshot, v)
+		trace = append(trace, snapshot)
+
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[max+k-1] < v[max+k+1]) {
+				x = v[max+k+1]
+			} else {
+				x = v[max+k-1] + 1
+			}
+			y := x - k
+
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+
+			v[max+k] = x
+
+			if x >= n && y >= m {
+				return backtrackEdits(a, b, trace, max)
+			}
+		}
+	}
+
+	return nil
+}
+
+func backtrackEdits(a, b []string, trace [][]int, max int) []diffEdit {
+	var edits []diffEdit
+	x, y := len(a), len(b)
+
+	for d := len(trace) - 1; d >= 0; d-- {
+		v := trace[d]
+		k := x - y
+
+		var prevK int
+		if k == -d || (k != d && v[max+k-1] < v[max+k+1]) {
+			prevK = k + 1
+		} else {
+			prevK = k - 1
+		}
+
+		prevX := v[max+prevK]
+		prevY := prevX - prevK
+
+		for x > prevX && y > prevY {
+			edits = append(edits, diffEdit{' ', a[x-1]})
+			x--
+			y--
+		}
+
+		if d > 0 {
+			if x == prevX {
+				edits = append(edits, diffEdit{'+', b[y-1]})
+				y--
+			} else {
+				edits = append(edits, diffEdit{'-', a[x-1]})
+				x--
+			}
+		}
+	}
+
+	for i, j := 0, len(edits)-1; i < j; i, j = i+1, j-1 {
+		edits[i], edits[j] = edits[j], edits[i]
+	}
+
+	return edits
+}
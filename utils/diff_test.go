@@ -0,0 +1,60 @@
+package utils
+
+import (
+	"testing"
+)
+
+func TestUnifiedDiff(t *testing.T) {
+	cases := []struct {
+		name string
+		a, b string
+		want string
+	}{
+		{name: "both empty", a: "", b: "", want: ""},
+		{name: "same", a: "a\nb\n", b: "a\nb\n", want: ""},
+		{
+			name: "from empty",
+			a:    "",
+			b:    "a\nb\n",
+			want: "--- a\n+++ b\n@@ -0,0 +1,2 @@\n+a\n+b\n",
+		},
+		{
+			name: "to empty",
+			a:    "a\n",
+			b:    "",
+			want: "--- a\n+++ b\n@@ -1 +0,0 @@\n-a\n",
+		},
+		{
+			name: "change",
+			a:    "a\nb\nc\n",
+			b:    "a\nB\nc\n",
+			want: "--- a\n+++ b\n@@ -1,3 +1,3 @@\n a\n-b\n+B\n c\n",
+		},
+		{
+			name: "no trailing newline",
+			a:    "a\nb",
+			b:    "a\nb\n",
+			want: "--- a\n+++ b\n@@ -1,2 +1,2 @@\n a\n-b\n\\ No newline at end of file\n+b\n",
+		},
+		{
+			name: "separate hunks",
+			a:    "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n",
+			b:    "0\n2\n3\n4\n5\n6\n7\n8\n9\nX\n",
+			want: "--- a\n+++ b\n@@ -1,4 +1,4 @@\n-1\n+0\n 2\n 3\n 4\n@@ -7,4 +7,4 @@\n 7\n 8\n 9\n-10\n+X\n",
+		},
+		{
+			name: "merged hunk",
+			a:    "1\n2\n3\n4\n5\n",
+			b:    "0\n2\n3\n4\n6\n",
+			want: "--- a\n+++ b\n@@ -1,5 +1,5 @@\n-1\n+0\n 2\n 3\n 4\n-5\n+6\n",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := UnifiedDiff("a", "b", []byte(c.a), []byte(c.b)); got != c.want {
+				t.Errorf("UnifiedDiff(%q, %q) =\n%s\nwant\n%s", c.a, c.b, got, c.want)
+			}
+		})
+	}
+}
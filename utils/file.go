@@ -2,8 +2,6 @@ package utils
 
 import (
 	"fmt"
-	"github.com/spf13/viper"
-	"go/token"
 	"golang.org/x/tools/imports"
 	"io"
 	"os"
@@ -21,44 +19,21 @@ func SaveToFile(filename string, content []byte) error {
 
 	_, err = writer.Write(content)
 
-	if viper.GetBool("debug") {
-		fmt.Printf("Save %s:\n%s", filename, content)
-	}
-
 	return err
 }
 
-func SaveGoCodeToFile(filename string, content []byte) error {
+// FormatGoCode 格式化生成的代码并整理 import，结果与 SaveGoCodeToFile 写入的内容相同
+func FormatGoCode(filename string, content []byte) ([]byte, error) {
 	// imports.Process 包含了 format.Source 所做的内容
-	content, err := imports.Process(filename, content, nil)
-
-	if err != nil {
-		return fmt.Errorf("cannot format generated code: %w", err)
-	}
-
-	return SaveToFile(filename, content)
+	return imports.Process(filename, content, nil)
 }
 
-func GetStringFromPosition(start token.Pos, end token.Pos) (string, error) {
-	startPos := fileSet.Position(start)
-	endPos := fileSet.Position(end)
-
-	f, err := os.Open(startPos.Filename)
-
-	if err != nil {
-		return "", fmt.Errorf("cannot read file %s: %w", startPos.Filename, err)
-	}
-
-	defer f.Close()
-
-	text := make([]byte, endPos.Offset-startPos.Offset)
-
-	_, _ = f.Seek(0, 0)
-	_, err = f.ReadAt(text, int64(startPos.Offset))
+func SaveGoCodeToFile(filename string, content []byte) error {
+	content, err := FormatGoCode(filename, content)
 
 	if err != nil {
-		return "", fmt.Errorf("cannot read file %s in [%d, %d): %w", startPos.Filename, startPos.Offset, endPos.Offset, err)
+		return fmt.Errorf("cannot format generated code: %w", err)
 	}
 
-	return ToString(text), nil
+	return SaveToFile(filename, content)
 }
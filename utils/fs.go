@@ -0,0 +1,54 @@
+package utils
+
+import (
+	"go/build"
+	"io"
+	"io/fs"
+	"path"
+	"strings"
+)
+
+// buildContext 返回从 fsys 中读取文件的 build.Context，路径为 fsys 中使用 / 分隔的路径
+func buildContext(fsys fs.FS) *build.Context {
+	ctxt := build.Default
+
+	ctxt.JoinPath = path.Join
+	ctxt.IsAbsPath = path.IsAbs
+	ctxt.SplitPathList = func(list string) []string {
+		return strings.Split(list, ":")
+	}
+	// 不在 GOROOT 与 GOPATH 中查找，只读取 fsys 中的文件
+	ctxt.HasSubdir = func(root, dir string) (string, bool) {
+		return "", false
+	}
+	ctxt.IsDir = func(name string) bool {
+		info, err := fs.Stat(fsys, name)
+
+		return err == nil && info.IsDir()
+	}
+	ctxt.ReadDir = func(dir string) ([]fs.FileInfo, error) {
+		entries, err := fs.ReadDir(fsys, dir)
+
+		if err != nil {
+			return nil, err
+		}
+
+		infos := make([]fs.FileInfo, 0, len(entries))
+		for _, entry := range entries {
+			info, err := entry.Info()
+
+			if err != nil {
+				return nil, err
+			}
+
+			infos = append(infos, info)
+		}
+
+		return infos, nil
+	}
+	ctxt.OpenFile = func(name string) (io.ReadCloser, error) {
+		return fsys.Open(name)
+	}
+
+	return &ctxt
+}
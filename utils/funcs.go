@@ -0,0 +1,217 @@
+package utils
+
+import (
+	"go/ast"
+	"go/parser"
+	"reflect"
+	"regexp"
+	"strings"
+	"text/template"
+	"unicode"
+)
+
+// TemplateFuncs 返回所有模板（内置模板、自定义模板、命名与文件名模板）都可以使用的函数，options 中的 Initialisms 会被用于大小写转换
+//
+//	snake "HTTPClient"         http_client
+//	kebab "userID"             user-id
+//	camel "UserID"             userID
+//	pascal "userId"            UserID，与推导方法名时相同，会处理缩写词
+//	plural "Category"          Categories
+//	tag $field "json"          字段 struct tag 中 json 的值，不存在时为空字符串
+//	isPointer "*int"           类型是否为指针，同理有 isSlice（含数组）与 isMap
+//	elemType "map[string]int"  指针、切片、数组、channel 的元素类型与 map 的 value 类型，其他类型为空字符串
+//	qualify "net/http" "Client"  http.Client，生成的代码格式化时会自动添加导入语句
+//	receiver $.struct          u *User
+//	receiverName "UserBuilder" ub
+func TemplateFuncs(options *Options) template.FuncMap {
+	var initialisms []string
+	if options != nil {
+		initialisms = options.Initialisms
+	}
+
+	return template.FuncMap{
+		"snake": ToSnakeName,
+		"kebab": ToKebabName,
+		"camel": func(name string) string {
+			return ToCamelName(name, initialisms...)
+		},
+		"pascal": func(name string) string {
+			return ToPascalName(name, initialisms...)
+		},
+		"plural": func(name string) string {
+			return ToPlural(name, initialisms...)
+		},
+		"tag":          getTag,
+		"isPointer":    IsPointerType,
+		"isSlice":      IsSliceType,
+		"isMap":        IsMapType,
+		"elemType":     GetElemType,
+		"qualify":      Qualify,
+		"receiver":     getReceiver,
+		"receiverName": GetShortName,
+	}
+}
+
+// ToSnakeName 将标识符转换为小写下划线形式，例如 HTTPClient -> http_client
+func ToSnakeName(name string) string {
+	return joinLowerWords(name, "_")
+}
+
+// ToKebabName 将标识符转换为小写连字符形式，例如 userID -> user-id
+func ToKebabName(name string) string {
+	return joinLowerWords(name, "-")
+}
+
+func joinLowerWords(name, sep string) string {
+	words := SplitWords(name)
+	for i, word := range words {
+		words[i] = strings.ToLower(word)
+	}
+
+	return strings.Join(words, sep)
+}
+
+// ToCamelName 将标识符转换为首字母小写的驼峰形式，开头的缩写词整体小写，例如 HTTPClient -> httpClient
+func ToCamelName(name string, initialisms ...string) string {
+	words := SplitWords(name)
+	if len(words) == 0 {
+		return ""
+	}
+
+	return strings.ToLower(words[0]) + ToPascalName(strings.Join(words[1:], "_"), initialisms...)
+}
+
+var irregularPlurals = map[string]string{
+	"child":  "children",
+	"foot":   "feet",
+	"goose":  "geese",
+	"man":    "men",
+	"mouse":  "mice",
+	"person": "people",
+	"tooth":  "teeth",
+	"woman":  "women",
+}
+
+// ToPlural 返回标识符最后一个单词的英文复数形式，例如 UserCategory -> UserCategories，ID -> IDs
+func ToPlural(name string, initialisms ...string) string {
+	words := SplitWords(name)
+	if len(words) == 0 {
+		return name
+	}
+
+	// 保留最后一个单词之后的分隔符，例如 user_ -> users_
+	word := words[len(words)-1]
+	index := strings.LastIndex(name, word)
+	prefix, suffix := name[:index], name[index+len(word):]
+	lower := strings.ToLower(word)
+
+	var plural string
+
+	switch {
+	case strings.ToUpper(word) == word && IsInitialism(word, initialisms...):
+		plural = word + "s"
+	case irregularPlurals[lower] != "":
+		plural = irregularPlurals[lower]
+		if unicode.IsUpper([]rune(word)[0]) {
+			plural = strings.ToUpper(plural[:1]) + plural[1:]
+		}
+	case strings.HasSuffix(lower, "s") || strings.HasSuffix(lower, "x") || strings.HasSuffix(lower, "z") ||
+		strings.HasSuffix(lower, "ch") || strings.HasSuffix(lower, "sh"):
+		plural = word + "es"
+	case len(lower) > 1 && lower[len(lower)-1] == 'y' && !strings.ContainsRune("aeiou", rune(lower[len(lower)-2])):
+		plural = word[:len(word)-1] + "ies"
+	default:
+		plural = word + "s"
+	}
+
+	return prefix + plural + suffix
+}
+
+func getTag(field *Field, key string) string {
+	return reflect.StructTag(field.Tag).Get(key)
+}
+
+// parseType 解析类型表达式，无法解析时返回 nil
+func parseType(typ string) ast.Expr {
+	expr, err := parser.ParseExpr(typ)
+	if err != nil {
+		return nil
+	}
+
+	if paren, ok := expr.(*ast.ParenExpr); ok {
+		return paren.X
+	}
+
+	return expr
+}
+
+// IsPointerType 判断类型是否为指针
+func IsPointerType(typ string) bool {
+	_, ok := parseType(typ).(*ast.StarExpr)
+
+	return ok
+}
+
+// IsSliceType 判断类型是否为切片或数组
+func IsSliceType(typ string) bool {
+	_, ok := parseType(typ).(*ast.ArrayType)
+
+	return ok
+}
+
+// IsMapType 判断类型是否为 map
+func IsMapType(typ string) bool {
+	_, ok := parseType(typ).(*ast.MapType)
+
+	return ok
+}
+
+// GetElemType 返回指针、切片、数组、channel 的元素类型与 map 的 value 类型，其他类型返回空字符串
+func GetElemType(typ string) string {
+	var elem ast.Expr
+
+	switch expr := parseType(typ).(type) {
+	case *ast.StarExpr:
+		elem = expr.X
+	case *ast.ArrayType:
+		elem = expr.Elt
+	case *ast.MapType:
+		elem = expr.Value
+	case *ast.ChanType:
+		elem = expr.Value
+	default:
+		return ""
+	}
+
+	// ParseExpr 的位置从 1 开始，截取原本的文本以保留原有的写法
+	return typ[elem.Pos()-1 : elem.End()-1]
+}
+
+var majorVersionSuffix = regexp.MustCompile(`^v[0-9]+$`)
+
+// Qualify 返回使用包名限定的名称，包名按照惯例取导入路径的最后一段（忽略 /v2 等版本后缀），例如 Qualify("net/http", "Client") -> http.Client
+func Qualify(path, name string) string {
+	if path == "" {
+		return name
+	}
+
+	elements := strings.Split(path, "/")
+	pkg := elements[len(elements)-1]
+
+	if majorVersionSuffix.MatchString(pkg) && len(elements) > 1 {
+		pkg = elements[len(elements)-2]
+	}
+
+	// gopkg.in/yaml.v2 与 go-xxx 这样的路径
+	pkg = strings.TrimPrefix(pkg, "go-")
+	if dot := strings.IndexByte(pkg, '.'); dot != -1 {
+		pkg = pkg[:dot]
+	}
+	pkg = strings.ReplaceAll(pkg, "-", "")
+
+	return pkg + "." + name
+}
+
+func getReceiver(s *Struct) string {
+	return s.ShortName + " *" + s.Name
+}
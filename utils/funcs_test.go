@@ -0,0 +1,32 @@
+package utils
+
+import (
+	"testing"
+)
+
+func TestToPlural(t *testing.T) {
+	cases := []struct {
+		name string
+		want string
+	}{
+		{name: "", want: ""},
+		{name: "user", want: "users"},
+		{name: "User", want: "Users"},
+		{name: "UserCategory", want: "UserCategories"},
+		{name: "day", want: "days"},
+		{name: "box", want: "boxes"},
+		{name: "branch", want: "branches"},
+		{name: "Person", want: "People"},
+		{name: "ID", want: "IDs"},
+		{name: "userID", want: "userIDs"},
+		{name: "user_name", want: "user_names"},
+		{name: "user_", want: "users_"},
+		{name: "user__", want: "users__"},
+	}
+
+	for _, c := range cases {
+		if got := ToPlural(c.name); got != c.want {
+			t.Errorf("ToPlural(%q) = %q, want %q", c.name, got, c.want)
+		}
+	}
+}
@@ -1,96 +1,100 @@
 package utils
 
 import (
-	"fmt"
-	"github.com/spf13/viper"
 	"go/ast"
-	"go/build"
-	"go/parser"
-	"strings"
+	"go/token"
 )
 
-type Function struct{}
+type Function struct {
+	Name string
+	Pos  token.Position // 方法定义的位置
+}
 
 type Functions map[string]*Function
 
 var GENERATED_BY_GOD = CompileRegex(`^Code generated by god.* DO NOT EDIT\.`)
 
-func GetFunctionsFromFileForStruct(f *ast.File, s *Struct) (Functions, error) {
-	result := make(Functions, 0)
+// GENERATED_BY_GOD_GENERATOR 用于从头部注释中提取生成器的名称，合并生成的文件没有生成器名称
+var GENERATED_BY_GOD_GENERATOR = CompileRegex(`^Code generated by god(?: ([^,\s]+))?,? DO NOT EDIT\.`)
 
+// GetGodGenerator 返回生成该文件的生成器名称，ok 表示文件是否由 god 生成
+//
+// 只检查 package 语句之前的注释
+func GetGodGenerator(f *ast.File) (generator string, ok bool) {
 	for _, comment := range f.Comments {
-		if GENERATED_BY_GOD.MatchString(comment.Text()) {
-			return result, nil
-		}
-	}
-
-	for _, decl := range f.Decls {
-		funcDecl, ok := decl.(*ast.FuncDecl)
-
-		if !ok || funcDecl.Recv == nil {
-			continue
+		if comment.Pos() > f.Package {
+			break
 		}
 
-		for _, field := range funcDecl.Recv.List {
-
-			fieldType, err := GetStringFromPosition(field.Type.Pos(), field.Type.End())
-
-			if err != nil {
-				return nil, fmt.Errorf("cannot get receiver's type: %w", err)
-			}
-
-			if strings.HasPrefix(fieldType, "*") {
-				fieldType = strings.TrimSpace(fieldType[1:])
-			}
-
-			if fieldType != s.Name {
-				continue
-			}
-
-			result[funcDecl.Name.String()] = nil
+		if match := GENERATED_BY_GOD_GENERATOR.FindStringSubmatch(comment.Text()); match != nil {
+			return match[1], true
 		}
 	}
 
-	return result, nil
+	return "", false
 }
 
-func GetFunctionsFromPackageForStruct(s *Struct) (Functions, error) {
-	results := make(Functions, 0)
-
-	pkgName := viper.GetString("gopackage")
-	if pkgName == "" {
-		return nil, fmt.Errorf("missing package name (gopackage config)")
-	}
-
-	// 获取包中所有的 Go 文件
-	pkgInfo, err := build.ImportDir(".", 0)
-	if err != nil {
-		return nil, fmt.Errorf("cannot build from package: %w", err)
-	}
+// IsGeneratedByGod 判断文件是否由 god 生成
+func IsGeneratedByGod(f *ast.File) bool {
+	_, ok := GetGodGenerator(f)
 
-	for _, filename := range pkgInfo.GoFiles {
-		f, err := parser.ParseFile(fileSet, filename, nil, parser.ParseComments)
+	return ok
+}
 
-		if err != nil {
-			return nil, fmt.Errorf("cannot parse file %s: %w", filename, err)
-		}
+// indexDeclarations 一次遍历包中的文件（不含 god 生成的文件），建立每个类型的方法索引与顶层的类型、函数索引
+func (p *Package) indexDeclarations() {
+	p.methods = make(map[string]Functions)
+	p.decls = make(Functions)
 
-		result, err := GetFunctionsFromFileForStruct(f, s)
-		if err != nil {
-			return nil, fmt.Errorf("cannot get functions from file %s: %w", filename, err)
+	for _, f := range p.files {
+		if IsGeneratedByGod(f) {
+			continue
 		}
 
-		for name, function := range result {
-			results[name] = function
+		for _, decl := range f.Decls {
+			switch decl := decl.(type) {
+			case *ast.FuncDecl:
+				function := &Function{
+					Name: decl.Name.String(),
+					Pos:  p.position(decl.Pos()),
+				}
+
+				if decl.Recv == nil {
+					p.decls[function.Name] = function
+					continue
+				}
+
+				for _, field := range decl.Recv.List {
+					typeName := GetReceiverTypeName(field.Type)
+					if p.methods[typeName] == nil {
+						p.methods[typeName] = make(Functions)
+					}
+
+					p.methods[typeName][function.Name] = function
+				}
+			case *ast.GenDecl:
+				if decl.Tok != token.TYPE {
+					continue
+				}
+
+				for _, spec := range decl.Specs {
+					if spec, ok := spec.(*ast.TypeSpec); ok {
+						p.decls[spec.Name.Name] = &Function{
+							Name: spec.Name.Name,
+							Pos:  p.position(spec.Pos()),
+						}
+					}
+				}
+			}
 		}
 	}
-
-	return results, nil
 }
 
-// TODO
-func GetFunctionsFromPackageForStructs(structs Structs) (map[*Struct]Functions, error) {
-	results := make(map[*Struct]Functions, len(structs))
+// methodsOf 返回类型在包中（不含 god 生成的文件）已经定义的方法
+func (p *Package) methodsOf(typeName string) Functions {
+	if functions, ok := p.methods[typeName]; ok {
+		return functions
+	}
 
-	return results, nil
+	return make(Functions)
 }
@@ -0,0 +1,48 @@
+package utils
+
+import (
+	"path"
+	"strings"
+)
+
+// MatchGlob 判断 name 是否匹配 pattern，语法与 path.Match 相同，另外支持 ** 匹配任意层目录
+func MatchGlob(pattern, name string) bool {
+	return matchSegments(strings.Split(pattern, "/"), strings.Split(name, "/"))
+}
+
+func matchSegments(patterns, names []string) bool {
+	for len(patterns) != 0 {
+		if patterns[0] == "**" {
+			for i := 0; i <= len(names); i++ {
+				if matchSegments(patterns[1:], names[i:]) {
+					return true
+				}
+			}
+
+			return false
+		}
+
+		if len(names) == 0 {
+			return false
+		}
+
+		if ok, err := path.Match(patterns[0], names[0]); err != nil || !ok {
+			return false
+		}
+
+		patterns, names = patterns[1:], names[1:]
+	}
+
+	return len(names) == 0
+}
+
+// MatchAnyGlob 判断 name 是否匹配任意一个 pattern
+func MatchAnyGlob(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		if MatchGlob(pattern, name) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,63 @@
+package utils
+
+import (
+	"testing"
+)
+
+func TestMatchGlob(t *testing.T) {
+	cases := []struct {
+		pattern string
+		name    string
+		want    bool
+	}{
+		{pattern: "model", name: "model", want: true},
+		{pattern: "model", name: "model/user", want: false},
+		{pattern: "model/*", name: "model/user", want: true},
+		{pattern: "model/*", name: "model/user/admin", want: false},
+		{pattern: "*/user", name: "model/user", want: true},
+		{pattern: "model/u?er", name: "model/user", want: true},
+		{pattern: "model/[a-t]*", name: "model/user", want: false},
+		{pattern: "**", name: "", want: true},
+		{pattern: "**", name: "model/user/admin", want: true},
+		{pattern: "**/user", name: "user", want: true},
+		{pattern: "**/user", name: "model/user", want: true},
+		{pattern: "**/user", name: "a/b/user", want: true},
+		{pattern: "**/user", name: "model/user/admin", want: false},
+		{pattern: "model/**", name: "model", want: true},
+		{pattern: "model/**", name: "model/user/admin", want: true},
+		{pattern: "model/**", name: "other/user", want: false},
+		{pattern: "a/**/z", name: "a/z", want: true},
+		{pattern: "a/**/z", name: "a/b/c/z", want: true},
+		{pattern: "a/**/z", name: "a/b/c", want: false},
+		{pattern: "[", name: "[", want: false},
+	}
+
+	for _, c := range cases {
+		if got := MatchGlob(c.pattern, c.name); got != c.want {
+			t.Errorf("MatchGlob(%q, %q) = %v, want %v", c.pattern, c.name, got, c.want)
+		}
+	}
+}
+
+func TestMatchAnyGlob(t *testing.T) {
+	patterns := []string{"model/*", "**/internal"}
+
+	cases := []struct {
+		name string
+		want bool
+	}{
+		{name: "model/user", want: true},
+		{name: "a/b/internal", want: true},
+		{name: "service", want: false},
+	}
+
+	for _, c := range cases {
+		if got := MatchAnyGlob(patterns, c.name); got != c.want {
+			t.Errorf("MatchAnyGlob(%q, %q) = %v, want %v", patterns, c.name, got, c.want)
+		}
+	}
+
+	if MatchAnyGlob(nil, "model") {
+		t.Errorf("MatchAnyGlob(nil, %q) = true, want false", "model")
+	}
+}
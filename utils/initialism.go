@@ -0,0 +1,116 @@
+package utils
+
+import (
+	"strings"
+	"unicode"
+)
+
+// commonInitialisms 来自 golint，可以通过 Options.Initialisms 扩展
+var commonInitialisms = map[string]bool{
+	"ACL":   true,
+	"API":   true,
+	"ASCII": true,
+	"CPU":   true,
+	"CSS":   true,
+	"DNS":   true,
+	"EOF":   true,
+	"GUID":  true,
+	"HTML":  true,
+	"HTTP":  true,
+	"HTTPS": true,
+	"ID":    true,
+	"IP":    true,
+	"JSON":  true,
+	"LHS":   true,
+	"QPS":   true,
+	"RAM":   true,
+	"RHS":   true,
+	"RPC":   true,
+	"SLA":   true,
+	"SMTP":  true,
+	"SQL":   true,
+	"SSH":   true,
+	"TCP":   true,
+	"TLS":   true,
+	"TTL":   true,
+	"UDP":   true,
+	"UI":    true,
+	"UID":   true,
+	"UUID":  true,
+	"URI":   true,
+	"URL":   true,
+	"UTF8":  true,
+	"VM":    true,
+	"XML":   true,
+	"XMPP":  true,
+	"XSRF":  true,
+	"XSS":   true,
+}
+
+// IsInitialism 判断单词（不区分大小写）是否为缩写词，initialisms 为额外的缩写词
+func IsInitialism(word string, initialisms ...string) bool {
+	word = strings.ToUpper(word)
+
+	if commonInitialisms[word] {
+		return true
+	}
+
+	for _, initialism := range initialisms {
+		if strings.ToUpper(initialism) == word {
+			return true
+		}
+	}
+
+	return false
+}
+
+// SplitWords 将标识符按照大小写变化和 _ - 分隔符拆分为单词，例如 httpClient -> [http Client]，HTTPClient -> [HTTP Client]
+func SplitWords(name string) []string {
+	var words []string
+
+	runes := []rune(name)
+	start := 0
+
+	flush := func(end int) {
+		if end > start {
+			words = append(words, string(runes[start:end]))
+		}
+		start = end
+	}
+
+	for i, c := range runes {
+		switch {
+		case c == '_' || c == '-':
+			flush(i)
+			start = i + 1
+		case i > start && unicode.IsUpper(c) && !unicode.IsUpper(runes[i-1]):
+			// fooBar, foo1Bar
+			flush(i)
+		case i > start && unicode.IsUpper(c) && unicode.IsUpper(runes[i-1]) && i+1 < len(runes) && unicode.IsLower(runes[i+1]):
+			// HTTPClient
+			flush(i)
+		}
+	}
+
+	flush(len(runes))
+
+	return words
+}
+
+// ToPascalName 将标识符转换为首字母大写的驼峰形式，并按照 Go 的习惯处理缩写词，例如 userId -> UserID，url -> URL
+func ToPascalName(name string, initialisms ...string) string {
+	b := strings.Builder{}
+
+	for _, word := range SplitWords(name) {
+		if IsInitialism(word, initialisms...) {
+			b.WriteString(strings.ToUpper(word))
+			continue
+		}
+
+		w := []rune(word)
+		b.WriteRune(unicode.ToUpper(w[0]))
+		b.WriteString(string(w[1:]))
+	}
+
+	return b.String()
+}
@@ -0,0 +1,57 @@
+package utils
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSplitWords(t *testing.T) {
+	cases := []struct {
+		name string
+		want []string
+	}{
+		{name: "", want: nil},
+		{name: "name", want: []string{"name"}},
+		{name: "httpClient", want: []string{"http", "Client"}},
+		{name: "HTTPClient", want: []string{"HTTP", "Client"}},
+		{name: "userIDList", want: []string{"user", "ID", "List"}},
+		{name: "utf8Reader", want: []string{"utf8", "Reader"}},
+		{name: "foo1Bar", want: []string{"foo1", "Bar"}},
+		{name: "user_id", want: []string{"user", "id"}},
+		{name: "user-name", want: []string{"user", "name"}},
+		{name: "_private__name_", want: []string{"private", "name"}},
+		{name: "ID", want: []string{"ID"}},
+	}
+
+	for _, c := range cases {
+		if got := SplitWords(c.name); !reflect.DeepEqual(got, c.want) {
+			t.Errorf("SplitWords(%q) = %q, want %q", c.name, got, c.want)
+		}
+	}
+}
+
+func TestToPascalName(t *testing.T) {
+	cases := []struct {
+		name        string
+		initialisms []string
+		want        string
+	}{
+		{name: "name", want: "Name"},
+		{name: "url", want: "URL"},
+		{name: "userId", want: "UserID"},
+		{name: "userIDList", want: "UserIDList"},
+		{name: "utf8Reader", want: "UTF8Reader"},
+		{name: "httpsProxy", want: "HTTPSProxy"},
+		{name: "user_id", want: "UserID"},
+		{name: "Name", want: "Name"},
+		{name: "grpcServer", want: "GrpcServer"},
+		{name: "grpcServer", initialisms: []string{"GRPC"}, want: "GRPCServer"},
+		{name: "grpcServer", initialisms: []string{"grpc"}, want: "GRPCServer"},
+	}
+
+	for _, c := range cases {
+		if got := ToPascalName(c.name, c.initialisms...); got != c.want {
+			t.Errorf("ToPascalName(%q, %q) = %q, want %q", c.name, c.initialisms, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,128 @@
+package utils
+
+import (
+	"bytes"
+	"io"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+	"time"
+)
+
+// memoryFS 是只读的内存文件系统，key 为使用 / 分隔的文件名，目录由文件名推导
+type memoryFS map[string][]byte
+
+func (fsys memoryFS) Open(name string) (fs.File, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrInvalid}
+	}
+
+	if content, ok := fsys[name]; ok {
+		return &memoryFile{
+			info:   memoryFileInfo{name: path.Base(name), size: int64(len(content))},
+			Reader: bytes.NewReader(content),
+		}, nil
+	}
+
+	prefix := name + "/"
+	if name == "." {
+		prefix = ""
+	}
+
+	children := make(map[string]memoryFileInfo)
+	for filename, content := range fsys {
+		if !strings.HasPrefix(filename, prefix) {
+			continue
+		}
+
+		rest := filename[len(prefix):]
+		if i := strings.IndexByte(rest, '/'); i != -1 {
+			children[rest[:i]] = memoryFileInfo{name: rest[:i], dir: true}
+		} else {
+			children[rest] = memoryFileInfo{name: rest, size: int64(len(content))}
+		}
+	}
+
+	if len(children) == 0 && name != "." {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+	}
+
+	entries := make([]fs.DirEntry, 0, len(children))
+	for _, info := range children {
+		entries = append(entries, info)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Name() < entries[j].Name()
+	})
+
+	return &memoryDir{
+		info:    memoryFileInfo{name: path.Base(name), dir: true},
+		entries: entries,
+	}, nil
+}
+
+// memoryFileInfo 同时实现 fs.FileInfo 与 fs.DirEntry
+type memoryFileInfo struct {
+	name string
+	size int64
+	dir  bool
+}
+
+func (i memoryFileInfo) Name() string               { return i.name }
+func (i memoryFileInfo) Size() int64                { return i.size }
+func (i memoryFileInfo) ModTime() time.Time         { return time.Time{} }
+func (i memoryFileInfo) IsDir() bool                { return i.dir }
+func (i memoryFileInfo) Sys() interface{}           { return nil }
+func (i memoryFileInfo) Type() fs.FileMode          { return i.Mode().Type() }
+func (i memoryFileInfo) Info() (fs.FileInfo, error) { return i, nil }
+
+func (i memoryFileInfo) Mode() fs.FileMode {
+	if i.dir {
+		return fs.ModeDir | 0755
+	}
+
+	return 0644
+}
+
+type memoryFile struct {
+	info memoryFileInfo
+	*bytes.Reader
+}
+
+func (f *memoryFile) Stat() (fs.FileInfo, error) { return f.info, nil }
+func (f *memoryFile) Close() error               { return nil }
+
+type memoryDir struct {
+	info    memoryFileInfo
+	entries []fs.DirEntry
+	offset  int
+}
+
+func (d *memoryDir) Stat() (fs.FileInfo, error) { return d.info, nil }
+func (d *memoryDir) Close() error               { return nil }
+
+func (d *memoryDir) Read([]byte) (int, error) {
+	return 0, &fs.PathError{Op: "read", Path: d.info.name, Err: fs.ErrInvalid}
+}
+
+// ReadDir 实现 fs.ReadDirFile，n > 0 时每次最多返回 n 个，读完后返回 io.EOF
+func (d *memoryDir) ReadDir(n int) ([]fs.DirEntry, error) {
+	rest := d.entries[d.offset:]
+
+	if n <= 0 {
+		d.offset = len(d.entries)
+		return rest, nil
+	}
+
+	if len(rest) == 0 {
+		return nil, io.EOF
+	}
+
+	if n > len(rest) {
+		n = len(rest)
+	}
+	d.offset += n
+
+	return rest[:n], nil
+}
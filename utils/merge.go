@@ -0,0 +1,114 @@
+package utils
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"path"
+	"strconv"
+)
+
+// COMBINED_HEADER 是合并后的文件使用的头部注释，可以被 GENERATED_BY_GOD 匹配
+const COMBINED_HEADER = "// Code generated by god, DO NOT EDIT."
+
+// MergeGoCode 将多段生成的代码合并为一个文件，合并并去重所有的 import
+//
+// 每段代码会先通过 FormatGoCode 去除未使用的 import，同一个名称对应不同的包时返回错误，头部会添加 options 中的 Header
+func MergeGoCode(filename string, contents [][]byte, options *Options) ([]byte, error) {
+	fset := token.NewFileSet()
+
+	packageName := ""
+	imports := make([]string, 0)
+	importedPaths := make(map[string]string) // 本地名称 -> 包路径
+	seen := make(map[string]bool)
+	body := bytes.NewBuffer(make([]byte, 0, 4096))
+
+	formatted := make([][]byte, len(contents))
+
+	err := RunParallel(options.Workers(), len(contents), func(i int) error {
+		content, err := FormatGoCode(filename, contents[i])
+
+		if err != nil {
+			return fmt.Errorf("cannot format part %d: %w", i, err)
+		}
+
+		formatted[i] = content
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	for i, content := range formatted {
+		f, err := parser.ParseFile(fset, "", content, parser.ParseComments)
+
+		if err != nil {
+			return nil, fmt.Errorf("cannot parse part %d: %w", i, err)
+		}
+
+		if packageName == "" {
+			packageName = f.Name.Name
+		} else if packageName != f.Name.Name {
+			return nil, fmt.Errorf("cannot merge package %s with package %s", f.Name.Name, packageName)
+		}
+
+		for _, spec := range f.Imports {
+			importPath, _ := strconv.Unquote(spec.Path.Value)
+			name := path.Base(importPath)
+			statement := spec.Path.Value
+
+			if spec.Name != nil {
+				name = spec.Name.Name
+				statement = spec.Name.Name + " " + spec.Path.Value
+			}
+
+			if name != "_" && name != "." {
+				if existed, ok := importedPaths[name]; ok && existed != importPath {
+					return nil, fmt.Errorf("import name %s is used by both %s and %s", name, existed, importPath)
+				}
+				importedPaths[name] = importPath
+			}
+
+			if !seen[statement] {
+				seen[statement] = true
+				imports = append(imports, statement)
+			}
+		}
+
+		for _, decl := range f.Decls {
+			if genDecl, ok := decl.(*ast.GenDecl); ok && genDecl.Tok == token.IMPORT {
+				continue
+			}
+
+			err := printer.Fprint(body, fset, &printer.CommentedNode{Node: decl, Comments: f.Comments})
+
+			if err != nil {
+				return nil, fmt.Errorf("cannot print part %d: %w", i, err)
+			}
+
+			body.WriteString("\n\n")
+		}
+	}
+
+	result := bytes.NewBuffer(make([]byte, 0, body.Len()+1024))
+	result.WriteString(options.HeaderComment())
+	result.WriteString(COMBINED_HEADER + "\n\n")
+	fmt.Fprintf(result, "package %s\n\n", packageName)
+
+	if len(imports) != 0 {
+		result.WriteString("import (\n")
+		for _, statement := range imports {
+			fmt.Fprintf(result, "\t%s\n", statement)
+		}
+		result.WriteString(")\n\n")
+	}
+
+	result.Write(body.Bytes())
+
+	return result.Bytes(), nil
+}
@@ -59,59 +59,5 @@ func IsPrivate(name string) bool {
 }
 
 func ShouldIgnore(name string) bool {
-	return name == "" || !IsASCII(name[0])
-}
-
-func ToGetterName(name string) (string, error) {
-	if name == "" {
-		return "", fmt.Errorf("name cannot be empty")
-	}
-	if !IsASCII(name[0]) {
-		return "", fmt.Errorf("name %s must started with an ASCII letter", name)
-	}
-	if IsUpper(name[0]) {
-		return "", fmt.Errorf("name %s must started with a lower ASCII letter", name)
-	}
-
-	return toGetterName(name), nil
-}
-
-func ToSetterName(name string) (string, error) {
-	if name == "" {
-		return "", fmt.Errorf("name cannot be empty")
-	}
-	if !IsASCII(name[0]) {
-		return "", fmt.Errorf("name %s must started with an ASCII letter", name)
-	}
-
-	return toSetterName(name), nil
-}
-
-func toGetterName(name string) string {
-	getterName := strings.Builder{}
-
-	n := []rune(name)
-
-	getterName.WriteRune(unicode.ToUpper(n[0]))
-
-	for _, c := range n[1:] {
-		getterName.WriteRune(c)
-	}
-
-	return getterName.String()
-}
-
-func toSetterName(name string) string {
-	setterName := strings.Builder{}
-	setterName.WriteString("Set")
-
-	n := []rune(name)
-
-	setterName.WriteRune(unicode.ToUpper(n[0]))
-
-	for _, c := range n[1:] {
-		setterName.WriteRune(c)
-	}
-
-	return setterName.String()
+	return name == "" || name == "_" || !IsASCII(name[0])
 }
@@ -0,0 +1,151 @@
+package utils
+
+import (
+	"bytes"
+	"fmt"
+	"go/token"
+	"strings"
+	"text/template"
+)
+
+// 内置的命名策略
+const (
+	NAMING_GO   = "go"   // Name / SetName
+	NAMING_JAVA = "java" // GetName / SetName
+)
+
+// bool 字段 Getter 可选的前缀
+var boolPrefixes = map[string]string{
+	"is":  "Is",  // IsName
+	"has": "Has", // HasName
+}
+
+// DeriveMethodNames 根据命名选项推导结构体各字段的 Getter/Setter 名称，tag 中指定的名称优先
+//
+// 选项：
+//
+//	Naming      内置的命名策略，go 或 java
+//	BoolPrefix  bool 字段 Getter 的前缀，is 或 has，为空时与其他字段相同
+//	GetterName  自定义 Getter 名称的模板，优先于 Naming
+//	SetterName  自定义 Setter 名称的模板，优先于 Naming
+//
+// 自定义模板中可以使用 $.struct、$.field 以及 $.name（按照 Go 习惯转换后的 BaseName，例如 UserID）
+func DeriveMethodNames(s *Struct, options *Options) error {
+	if err := options.Check(); err != nil {
+		return err
+	}
+
+	strategy := options.Naming
+	if strategy == "" {
+		strategy = NAMING_GO
+	}
+
+	boolPrefix := options.BoolPrefix
+
+	getterTemplate, err := parseNamingTemplate("getter-name", options.GetterName, options)
+	if err != nil {
+		return err
+	}
+
+	setterTemplate, err := parseNamingTemplate("setter-name", options.SetterName, options)
+	if err != nil {
+		return err
+	}
+
+	for _, field := range s.Fields {
+		if field.ShouldIgnore {
+			continue
+		}
+
+		name := ToPascalName(field.BaseName, options.Initialisms...)
+		data := map[string]interface{}{
+			"struct": s,
+			"field":  field,
+			"name":   name,
+		}
+
+		if !field.IsPublic {
+			switch {
+			case field.tagOptions != nil && field.tagOptions.GetterName != "":
+				field.GetterName = field.tagOptions.GetterName
+			case getterTemplate != nil:
+				field.GetterName, err = executeNamingTemplate(getterTemplate, data)
+			case field.Type == "bool" && boolPrefix != "":
+				field.GetterName = withPrefix(boolPrefixes[boolPrefix], name)
+			case strategy == NAMING_JAVA:
+				field.GetterName = "Get" + name
+			default:
+				field.GetterName = name
+			}
+
+			if err != nil {
+				return fmt.Errorf("cannot derive getter name for field %s: %w", field.Name, err)
+			}
+		}
+
+		if field.WillGenerateSetter {
+			switch {
+			case field.tagOptions != nil && field.tagOptions.SetterName != "":
+				field.SetterName = field.tagOptions.SetterName
+			case setterTemplate != nil:
+				field.SetterName, err = executeNamingTemplate(setterTemplate, data)
+			default:
+				field.SetterName = "Set" + name
+			}
+
+			if err != nil {
+				return fmt.Errorf("cannot derive setter name for field %s: %w", field.Name, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// withPrefix 为名称添加前缀，如果名称本身已经以任意一个 bool 前缀作为第一个单词（例如 IsActive、HasChildren）则保持不变，
+// 因此使用 has 时 isActive 的 Getter 为 IsActive 而不是 HasIsActive
+func withPrefix(prefix, name string) string {
+	words := SplitWords(name)
+
+	if len(words) > 1 {
+		for _, p := range boolPrefixes {
+			if words[0] == p {
+				return name
+			}
+		}
+	}
+
+	return prefix + name
+}
+
+func parseNamingTemplate(key, text string, options *Options) (*template.Template, error) {
+	if text == "" {
+		return nil, nil
+	}
+
+	t, err := template.New(key).Funcs(TemplateFuncs(options)).Parse(text)
+
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s template: %w", key, err)
+	}
+
+	return t, nil
+}
+
+func executeNamingTemplate(t *template.Template, data interface{}) (string, error) {
+	b := bytes.NewBuffer(make([]byte, 0, 32))
+
+	err := t.Execute(b, data)
+
+	if err != nil {
+		return "", err
+	}
+
+	name := strings.TrimSpace(b.String())
+
+	if !token.IsIdentifier(name) {
+		return "", fmt.Errorf("template %s produced invalid name %q", t.Name(), name)
+	}
+
+	return name, nil
+}
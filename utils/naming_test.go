@@ -0,0 +1,27 @@
+package utils
+
+import (
+	"testing"
+)
+
+func TestWithPrefix(t *testing.T) {
+	cases := []struct {
+		prefix string
+		name   string
+		want   string
+	}{
+		{prefix: "Is", name: "Active", want: "IsActive"},
+		{prefix: "Has", name: "Children", want: "HasChildren"},
+		{prefix: "Is", name: "IsActive", want: "IsActive"},
+		{prefix: "Has", name: "IsActive", want: "IsActive"},
+		{prefix: "Is", name: "HasChildren", want: "HasChildren"},
+		{prefix: "Is", name: "Is", want: "IsIs"},
+		{prefix: "Has", name: "Island", want: "HasIsland"},
+	}
+
+	for _, c := range cases {
+		if got := withPrefix(c.prefix, c.name); got != c.want {
+			t.Errorf("withPrefix(%q, %q) = %q, want %q", c.prefix, c.name, got, c.want)
+		}
+	}
+}
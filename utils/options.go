@@ -0,0 +1,128 @@
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"runtime"
+	"strings"
+)
+
+// DEFAULT_FILENAME 是生成的文件名的默认模板
+const DEFAULT_FILENAME = "{{ $.struct.LowerName }}_{{ $.type }}.go"
+
+// Options 是加载包与生成代码时使用的所有选项，零值可以直接使用
+//
+// json 的名称与命令行参数、配置文件中的名称相同
+type Options struct {
+	// 选择结构体：依次使用 Annotated、Structs、All，都未指定时使用 File 中的所有结构体
+	Package   string   `json:"gopackage,omitempty"` // 生成的代码使用的包名，为空时使用目录中的包名
+	File      string   `json:"gofile,omitempty"`    // 文件名，相对于包所在的目录
+	Structs   []string `json:"struct,omitempty"`
+	All       bool     `json:"all,omitempty"`
+	Annotated bool     `json:"annotated,omitempty"` // 使用所有带有 //god: 注释指令的结构体
+	Include   []string `json:"include,omitempty"`   // 未指定 Structs 时只使用匹配的结构体
+	Exclude   []string `json:"exclude,omitempty"`   // 未指定 Structs 时跳过匹配的结构体
+
+	// 命名与顺序
+	Naming      string   `json:"naming,omitempty"`      // go 或 java，为空时为 go
+	BoolPrefix  string   `json:"bool-prefix,omitempty"` // is 或 has
+	GetterName  string   `json:"getter-name,omitempty"` // Getter 名称的模板
+	SetterName  string   `json:"setter-name,omitempty"` // Setter 名称的模板
+	Initialisms []string `json:"initialisms,omitempty"` // 额外的缩写词
+	Sort        string   `json:"sort,omitempty"`        // source 或 alpha，为空时为 source
+
+	// 输出
+	Filename  string `json:"filename,omitempty"`  // 文件名模板，为空时为 DEFAULT_FILENAME
+	Header    string `json:"header,omitempty"`    // 添加到生成的文件头部的注释
+	Templates string `json:"templates,omitempty"` // 自定义模板所在的目录
+	Jobs      int    `json:"jobs,omitempty"`      // 并发数量，为 0 时为 CPU 数量
+
+	NoTypeCheck bool `json:"no-typecheck,omitempty"` // 不检查生成的代码能否与包一起编译
+
+	// 生成器
+	BuilderPrefix string `json:"builder-prefix,omitempty"` // Builder 方法名的前缀，为空时为 DEFAULT_BUILDER_PREFIX
+
+	// Settings 会原样传给外部生成器，为 nil 时使用以上的选项
+	Settings map[string]interface{} `json:"-"`
+}
+
+// Check 检查选项是否有效
+func (options *Options) Check() error {
+	switch options.Naming {
+	case "", NAMING_GO, NAMING_JAVA:
+	default:
+		return fmt.Errorf("unknown naming strategy %s", options.Naming)
+	}
+
+	if _, ok := boolPrefixes[options.BoolPrefix]; options.BoolPrefix != "" && !ok {
+		return fmt.Errorf("unknown bool prefix %s", options.BoolPrefix)
+	}
+
+	switch options.Sort {
+	case "", ORDER_SOURCE, ORDER_ALPHA:
+	default:
+		return fmt.Errorf("unknown sort order %s", options.Sort)
+	}
+
+	return nil
+}
+
+// Workers 返回并发执行的任务数量
+func (options *Options) Workers() int {
+	if options.Jobs <= 0 {
+		return runtime.NumCPU()
+	}
+
+	return options.Jobs
+}
+
+// FilenameTemplate 返回文件名模板
+func (options *Options) FilenameTemplate() string {
+	if options.Filename == "" {
+		return DEFAULT_FILENAME
+	}
+
+	return options.Filename
+}
+
+// BuilderMethodPrefix 返回 Builder 方法名的前缀
+func (options *Options) BuilderMethodPrefix() string {
+	if options.BuilderPrefix == "" {
+		return DEFAULT_BUILDER_PREFIX
+	}
+
+	return options.BuilderPrefix
+}
+
+// HeaderComment 返回 Header 对应的注释，每行以 // 开头，没有配置时返回空字符串
+func (options *Options) HeaderComment() string {
+	header := strings.TrimSpace(options.Header)
+	if header == "" {
+		return ""
+	}
+
+	b := strings.Builder{}
+	for _, line := range strings.Split(header, "\n") {
+		b.WriteString(strings.TrimSpace("// " + strings.TrimRight(line, " \t")))
+		b.WriteByte('\n')
+	}
+	b.WriteByte('\n')
+
+	return b.String()
+}
+
+// AllSettings 返回传给外部生成器的配置
+func (options *Options) AllSettings() map[string]interface{} {
+	if options.Settings != nil {
+		return options.Settings
+	}
+
+	settings := make(map[string]interface{})
+
+	b, err := json.Marshal(options)
+	if err == nil {
+		_ = json.Unmarshal(b, &settings)
+	}
+
+	return settings
+}
@@ -0,0 +1,76 @@
+package utils
+
+import (
+	"go/token"
+	"sort"
+	"strings"
+)
+
+// 生成代码中结构体与字段的顺序
+const (
+	ORDER_SOURCE = "source" // 按照源码中的声明顺序
+	ORDER_ALPHA  = "alpha"  // 按照名称的字母顺序
+)
+
+// nameLess 不区分大小写比较名称，仅大小写不同时大写在前
+func nameLess(a, b string) bool {
+	la, lb := strings.ToLower(a), strings.ToLower(b)
+	if la != lb {
+		return la < lb
+	}
+
+	return a < b
+}
+
+func positionLess(a, b token.Position) bool {
+	if a.Filename != b.Filename {
+		return a.Filename < b.Filename
+	}
+
+	return a.Offset < b.Offset
+}
+
+// List 按照 order 返回所有字段
+func (fields Fields) List(order string) []*Field {
+	list := make([]*Field, 0, len(fields))
+	for _, field := range fields {
+		list = append(list, field)
+	}
+
+	alpha := order == ORDER_ALPHA
+
+	sort.Slice(list, func(i, j int) bool {
+		if alpha && list[i].Name != list[j].Name {
+			return nameLess(list[i].Name, list[j].Name)
+		}
+
+		return positionLess(list[i].Pos, list[j].Pos)
+	})
+
+	return list
+}
+
+// SortStructs 按照结构体所在的包的 Sort 选项对结构体排序
+func SortStructs(list []*Struct) {
+	alpha := len(list) != 0 && list[0].Package != nil && list[0].Package.Options.Sort == ORDER_ALPHA
+
+	sort.Slice(list, func(i, j int) bool {
+		if alpha && list[i].Name != list[j].Name {
+			return nameLess(list[i].Name, list[j].Name)
+		}
+
+		return positionLess(list[i].Pos, list[j].Pos)
+	})
+}
+
+// List 按照结构体所在的包的 Sort 选项返回所有结构体
+func (structs Structs) List() []*Struct {
+	list := make([]*Struct, 0, len(structs))
+	for _, s := range structs {
+		list = append(list, s)
+	}
+
+	SortStructs(list)
+
+	return list
+}
@@ -0,0 +1,279 @@
+package utils
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+)
+
+// Package 是加载后的包，解析的结果只属于这个包，多个包可以同时加载
+type Package struct {
+	Dir     string   // 包所在的目录
+	Name    string   // 生成的代码使用的包名
+	Options *Options // 加载时使用的选项
+	Structs Structs  // 按照 Options 选择的结构体
+
+	fsys      fs.FS
+	fileSet   *token.FileSet
+	filenames []string // 包中的 Go 文件，包含 Dir
+	fsDir     string   // 包在 fsys 中的目录
+	root      string   // 包在磁盘上的目录，用于解析 import，为空时为当前目录
+	paths     []string // 包中的 Go 文件在 fsys 中的路径
+	files     []*ast.File
+	sources   map[string][]byte    // 已经解析的文件的内容
+	methods   map[string]Functions // 类型名 -> 已经定义的方法，不含 god 生成的文件
+	decls     Functions            // 顶层的类型与函数，不含 god 生成的文件
+}
+
+// LoadPackage 解析 dir 中的包，并按照 options 选择结构体、推导方法名、检查冲突，options 为 nil 时使用默认选项
+//
+// 不会读取任何全局的配置，也不会改变工作目录
+func LoadPackage(dir string, options *Options) (*Package, error) {
+	p, err := loadPackage(os.DirFS(dir), ".", dir, options)
+
+	if err != nil {
+		return nil, err
+	}
+
+	p.root = dir
+
+	return p, nil
+}
+
+// LoadPackageFS 与 LoadPackage 相同，但从 fsys 中的 dir（使用 / 分隔的路径）读取包，例如内存中的 fstest.MapFS
+//
+// dir 同样是磁盘上存在的目录时（例如 fsys 为 os.DirFS(".")），类型检查在 dir 中解析 import，否则在当前目录中解析
+func LoadPackageFS(fsys fs.FS, dir string, options *Options) (*Package, error) {
+	p, err := loadPackage(fsys, dir, filepath.FromSlash(dir), options)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if info, err := os.Stat(p.Dir); err == nil && info.IsDir() {
+		p.root = p.Dir
+	}
+
+	return p, nil
+}
+
+// loadPackage 解析 fsys 中 fsDir 目录的包，dir 是文件名（用于输出位置与生成的文件）使用的目录
+func loadPackage(fsys fs.FS, fsDir string, dir string, options *Options) (*Package, error) {
+	if options == nil {
+		options = &Options{}
+	}
+
+	if err := options.Check(); err != nil {
+		return nil, err
+	}
+
+	pkgInfo, err := buildContext(fsys).ImportDir(fsDir, 0)
+	if err != nil {
+		return nil, fmt.Errorf("cannot build from package: %w", err)
+	}
+
+	p := &Package{
+		Dir:     dir,
+		Name:    options.Package,
+		Options: options,
+		fsys:    fsys,
+		fsDir:   fsDir,
+		fileSet: token.NewFileSet(),
+		sources: make(map[string][]byte),
+	}
+
+	if p.Name == "" {
+		p.Name = pkgInfo.Name
+	}
+
+	for _, filename := range pkgInfo.GoFiles {
+		p.filenames = append(p.filenames, filepath.Join(dir, filename))
+		p.paths = append(p.paths, path.Join(fsDir, filename))
+	}
+
+	// 只解析一次包中所有的文件，结构体与方法索引都使用解析的结果
+	err = p.parseFiles()
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case options.Annotated:
+		err = p.selectAnnotatedStructs()
+	case len(options.Structs) != 0 || options.All:
+		err = p.selectStructs()
+	default:
+		err = p.selectFileStructs()
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	// 没有指定结构体时使用 include/exclude 过滤
+	if len(options.Structs) == 0 {
+		p.filterStructs()
+	}
+
+	p.prepareStructs()
+
+	return p, nil
+}
+
+// parseFiles 读取并解析包中所有的文件，文件内容会被缓存用于 source
+func (p *Package) parseFiles() error {
+	p.files = make([]*ast.File, 0, len(p.filenames))
+
+	for i, filename := range p.filenames {
+		src, err := fs.ReadFile(p.fsys, p.paths[i])
+
+		if err != nil {
+			return fmt.Errorf("cannot read file %s: %w", filename, err)
+		}
+
+		f, err := parser.ParseFile(p.fileSet, filename, src, parser.ParseComments)
+
+		if err != nil {
+			return fmt.Errorf("cannot parse file %s: %w", filename, err)
+		}
+
+		p.sources[filename] = src
+		p.files = append(p.files, f)
+	}
+
+	return nil
+}
+
+// CheckFiles 使用 c 将包目录中生成的文件叠加到包中进行类型检查，参考 TypeChecker.CheckFiles
+func (p *Package) CheckFiles(c *TypeChecker, files []*GeneratedFile) error {
+	return c.CheckFiles(p.fsys, p.fsDir, p.root, files)
+}
+
+// position 返回 pos 在源码中的位置
+func (p *Package) position(pos token.Pos) token.Position {
+	return p.fileSet.Position(pos)
+}
+
+// source 返回 [start, end) 的源码
+func (p *Package) source(start token.Pos, end token.Pos) (string, error) {
+	startPos := p.position(start)
+	endPos := p.position(end)
+
+	src, ok := p.sources[startPos.Filename]
+	if !ok || startPos.Offset < 0 || endPos.Offset > len(src) || startPos.Offset > endPos.Offset {
+		return "", fmt.Errorf("cannot read file %s in [%d, %d)", startPos.Filename, startPos.Offset, endPos.Offset)
+	}
+
+	return string(src[startPos.Offset:endPos.Offset]), nil
+}
+
+// selectFileStructs 使用 File 中的所有结构体
+func (p *Package) selectFileStructs() error {
+	filename := p.Options.File
+	if filename == "" {
+		return fmt.Errorf("missing filename (gofile config)")
+	}
+
+	for i, file := range p.filenames {
+		if file != filepath.Join(p.Dir, filename) {
+			continue
+		}
+
+		structs, err := p.getStructsFromFile(p.files[i])
+
+		if err != nil {
+			return fmt.Errorf("cannot get structs from file %s: %w", filename, err)
+		}
+
+		p.Structs = structs
+
+		return nil
+	}
+
+	// 检查传递的 gofile 是否在包中，不在则报错
+	return fmt.Errorf("cannot find provided filename %s", filename)
+}
+
+// selectStructs 使用 Structs 指定的结构体，指定了 All 时使用包中所有的结构体
+func (p *Package) selectStructs() error {
+	p.Structs = make(Structs, len(p.Options.Structs))
+	for _, structName := range p.Options.Structs {
+		p.Structs[structName] = nil
+	}
+
+	for i, file := range p.filenames {
+		// 不为 god 生成的类型（例如 Builder）生成代码
+		if IsGeneratedByGod(p.files[i]) {
+			continue
+		}
+
+		tempStructs, err := p.getStructsFromFile(p.files[i])
+		if err != nil {
+			return fmt.Errorf("cannot get structs from file %s: %w", file, err)
+		}
+
+		for tempStructName, tempStruct := range tempStructs {
+			if _, ok := p.Structs[tempStructName]; ok || p.Options.All {
+				p.Structs[tempStructName] = tempStruct
+			}
+		}
+	}
+
+	for name, s := range p.Structs {
+		if s == nil {
+			return fmt.Errorf("cannot get struct %s from package", name)
+		}
+	}
+
+	return nil
+}
+
+// selectAnnotatedStructs 使用包中所有带有注释指令（例如 //god:getter）的结构体
+func (p *Package) selectAnnotatedStructs() error {
+	p.Structs = make(Structs)
+
+	for i, file := range p.filenames {
+		if IsGeneratedByGod(p.files[i]) {
+			continue
+		}
+
+		tempStructs, err := p.getStructsFromFile(p.files[i])
+		if err != nil {
+			return fmt.Errorf("cannot get structs from file %s: %w", file, err)
+		}
+
+		for name, s := range tempStructs {
+			if len(s.Annotations) != 0 {
+				p.Structs[name] = s
+			}
+		}
+	}
+
+	return nil
+}
+
+// filterStructs 使用 include/exclude 过滤结构体
+func (p *Package) filterStructs() {
+	for name := range p.Structs {
+		if (len(p.Options.Include) != 0 && !MatchAnyGlob(p.Options.Include, name)) || MatchAnyGlob(p.Options.Exclude, name) {
+			delete(p.Structs, name)
+		}
+	}
+}
+
+// prepareStructs 检查已经存在与相互冲突的方法
+func (p *Package) prepareStructs() {
+	p.indexDeclarations()
+
+	for _, s := range p.Structs {
+		s.Methods = p.methodsOf(s.Name)
+		DisableExistedMethods(s, s.Methods)
+		DisableConflictedMethods(s)
+		p.deriveBuilderMethods(s)
+	}
+}
@@ -0,0 +1,70 @@
+package utils
+
+import (
+	"strings"
+	"sync"
+)
+
+// Errors 汇总多个错误
+type Errors []error
+
+func (errs Errors) Error() string {
+	messages := make([]string, 0, len(errs))
+	for _, err := range errs {
+		messages = append(messages, err.Error())
+	}
+
+	return strings.Join(messages, "\n")
+}
+
+// ErrorOrNil 在没有错误时返回 nil，只有一个错误时直接返回该错误
+func (errs Errors) ErrorOrNil() error {
+	switch len(errs) {
+	case 0:
+		return nil
+	case 1:
+		return errs[0]
+	default:
+		return errs
+	}
+}
+
+// RunParallel 使用最多 workers 个 goroutine 执行 n 个任务，所有任务都会执行，返回按任务顺序排列的所有错误
+func RunParallel(workers, n int, task func(i int) error) error {
+	if workers > n {
+		workers = n
+	}
+	if workers < 1 {
+		workers = 1
+	}
+
+	errs := make([]error, n)
+	indexes := make(chan int)
+	wg := sync.WaitGroup{}
+
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for i := range indexes {
+				errs[i] = task(i)
+			}
+		}()
+	}
+
+	for i := 0; i < n; i++ {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	result := make(Errors, 0)
+	for _, err := range errs {
+		if err != nil {
+			result = append(result, err)
+		}
+	}
+
+	return result.ErrorOrNil()
+}
@@ -0,0 +1,90 @@
+package utils
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"go/ast"
+	"go/build"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// PackageSignature 计算包中影响生成结果的内容的 hash：结构体定义（含注释与 tag）、方法集合以及 go:generate 指令
+//
+// 由 god 生成的文件会被忽略，generated 返回这些文件的路径
+func PackageSignature(dir string) (signature string, generated []string, err error) {
+	pkgInfo, err := build.ImportDir(dir, 0)
+
+	if err != nil {
+		return "", nil, fmt.Errorf("cannot build from package %s: %w", dir, err)
+	}
+
+	fset := token.NewFileSet()
+	parts := make([]string, 0)
+
+	for _, name := range pkgInfo.GoFiles {
+		filename := filepath.Join(dir, name)
+		src, err := ioutil.ReadFile(filename)
+
+		if err != nil {
+			return "", nil, fmt.Errorf("cannot read file %s: %w", filename, err)
+		}
+
+		if IsGodGeneratedSource(src) {
+			generated = append(generated, filename)
+			continue
+		}
+
+		f, err := parser.ParseFile(fset, filename, src, parser.ParseComments)
+
+		if err != nil {
+			return "", nil, fmt.Errorf("cannot parse file %s: %w", filename, err)
+		}
+
+		for _, comment := range f.Comments {
+			for _, c := range comment.List {
+				if strings.HasPrefix(c.Text, "//go:generate") || strings.HasPrefix(c.Text, ANNOTATION_PREFIX) {
+					parts = append(parts, name+" "+c.Text)
+				}
+			}
+		}
+
+		for _, decl := range f.Decls {
+			switch decl := decl.(type) {
+			case *ast.GenDecl:
+				if decl.Tok != token.TYPE {
+					continue
+				}
+
+				b := bytes.Buffer{}
+				if err := printer.Fprint(&b, fset, &printer.CommentedNode{Node: decl, Comments: f.Comments}); err != nil {
+					return "", nil, err
+				}
+
+				parts = append(parts, "type "+b.String())
+			case *ast.FuncDecl:
+				if decl.Recv == nil || len(decl.Recv.List) == 0 {
+					continue
+				}
+
+				parts = append(parts, "method "+GetReceiverTypeName(decl.Recv.List[0].Type)+"."+decl.Name.Name)
+			}
+		}
+	}
+
+	sort.Strings(parts)
+
+	h := sha256.New()
+	for _, part := range parts {
+		_, _ = fmt.Fprintf(h, "%s\x00", part)
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), generated, nil
+}
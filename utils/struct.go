@@ -3,20 +3,18 @@ package utils
 import (
 	"bytes"
 	"fmt"
-	"github.com/spf13/viper"
 	"go/ast"
-	"go/build"
-	"go/parser"
 	"go/token"
-	"os"
+	"sort"
+	"strconv"
 	"strings"
 )
 
-var fileSet *token.FileSet
-
 type Field struct {
-	Name string // 字段名
-	Type string // 字段类型
+	Name     string // 字段名
+	Type     string // 字段类型
+	BaseName string // 推导方法名时使用的名称，默认与字段名相同，可以通过 tag 的 name 选项覆盖
+	Tag      string // 原始的 struct tag（不含反引号）
 
 	GetterName         string // Getter 的名称
 	GetterAlreadyExist bool   // Getter 是否在原本的代码中就存在，含同名 field 已经存在的情况
@@ -28,9 +26,20 @@ type Field struct {
 
 	IsPublic     bool
 	ShouldIgnore bool
+	Skip         bool // 通过 //god:skip 注释跳过，不生成任何方法
 	HasGetter    bool
 
 	IgnoreReason string
+	Pos          token.Position // 字段在源码中的位置
+
+	GetterSkipped *Diagnostic // Getter 不会生成的原因
+	SetterSkipped *Diagnostic // Setter 不会生成的原因
+
+	BuilderName         string      // Builder 中设置该字段的方法名，例如 WithName
+	WillGenerateBuilder bool        // 是否要在 Builder 中生成设置该字段的方法
+	BuilderSkipped      *Diagnostic // Builder 中不会生成该方法的原因
+
+	tagOptions *TagOptions
 }
 
 type Fields map[string]*Field
@@ -39,94 +48,137 @@ type Struct struct {
 	Name      string // 结构体名称
 	ShortName string // 生成的函数中用于引用结构体的名称
 	LowerName string
-	IsPresent bool   // 结构体在包中存在
-	Fields    Fields // 结构体包含的成员
+	IsPresent bool     // 结构体在包中存在
+	Fields    Fields   // 结构体包含的成员
+	FieldList []*Field // 按照 sort 配置排序的成员，默认为源码中的声明顺序
+
+	Pos         token.Position // 结构体在源码中的位置
+	Warnings    Diagnostics    // 生成过程中产生的警告
+	Annotations []*Annotation  // 类型定义上的注释指令，例如 //god:getter
+	Methods     Functions      // 结构体在包中（不含 god 生成的文件）已经定义的方法
+	Package     *Package       // 结构体所在的包
+
+	BuilderSkipped  *Diagnostic // 不生成 Builder 的原因，例如 XBuilder 已经存在
+	BuilderWarnings Diagnostics // Builder 的警告，只在生成 Builder 时输出
+	BuildExists     bool        // XBuilder 上已经定义了 Build 方法，不再生成
 
 	ImportedStatements string // 这个 struct 定义可能需要依赖的导入语句
 }
 
 type Structs map[string]*Struct
 
-func GetFieldsFromStruct(structType *ast.StructType) (fields Fields, err error) {
+// FieldOfMethod 返回 getter、setter 或 Builder 方法名称为 name 的字段，没有时返回 nil
+func (s *Struct) FieldOfMethod(name string) *Field {
+	for _, field := range s.FieldList {
+		if field.GetterName == name || field.SetterName == name || field.BuilderName == name {
+			return field
+		}
+	}
+
+	return nil
+}
+
+func (p *Package) getFieldsFromStruct(structType *ast.StructType) (fields Fields, err error) {
 	fields = make(Fields, len(structType.Fields.List)<<1)
 
 	for _, field := range structType.Fields.List {
-		willGenerateGetter := true
-		willGenerateSetter := true
+		options := &TagOptions{}
+		tag := ""
 
 		if field.Tag != nil {
-			tag := field.Tag.Value
+			var err error
+			tag, err = strconv.Unquote(field.Tag.Value)
+
+			if err != nil {
+				return nil, fmt.Errorf("%s: invalid struct tag: %w", p.position(field.Tag.Pos()), err)
+			}
+
+			options, err = ParseTag(tag)
+
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", p.position(field.Tag.Pos()), err)
+			}
+
+			if (options.Name != "" || options.GetterName != "" || options.SetterName != "") && len(field.Names) > 1 {
+				return nil, fmt.Errorf("%s: name options of %s tag cannot be applied to multiple fields", p.position(field.Tag.Pos()), TAG_NAME)
+			}
+		}
+
+		skip := false
+
+		for _, annotation := range p.getAnnotations(field.Doc, field.Comment) {
+			if annotation.Name != ANNOTATION_SKIP {
+				return nil, fmt.Errorf("%s: unknown annotation %s on field, only %s%s is supported", annotation.Pos, annotation, ANNOTATION_PREFIX, ANNOTATION_SKIP)
+			}
 
-			willGenerateGetter = !strings.Contains(tag, `getter:"disable"`)
-			willGenerateSetter = !strings.Contains(tag, `setter:"disable"`)
+			skip = true
 		}
 
 		for _, name := range field.Names {
 			if ShouldIgnore(name.Name) {
-				fields[name.Name] = &Field{
+				theField := &Field{
 					Name:         name.Name,
 					ShouldIgnore: true,
-					IgnoreReason: "name is invalid",
+					Skip:         skip,
+					Pos:          p.position(name.Pos()),
 				}
+				theField.skipGetter(nil, REASON_INVALID_NAME, "name is invalid")
+				theField.skipSetter(nil, REASON_INVALID_NAME, "name is invalid")
+
+				fields[name.Name] = theField
 
 				continue
 			}
 
 			// type 的内容
-			startPosition := fileSet.Position(field.Type.Pos())
-			endPosition := fileSet.Position(field.Type.End())
-
-			file, err := os.Open(startPosition.Filename)
-
-			if err != nil {
-				return nil, fmt.Errorf("cannot read file %s: %w", startPosition.Filename, err)
-			}
-
-			defer file.Close()
-
-			b := make([]byte, endPosition.Offset-startPosition.Offset)
-			_, err = file.ReadAt(b, int64(startPosition.Offset))
+			fieldType, err := p.source(field.Type.Pos(), field.Type.End())
 
 			if err != nil {
-				return nil, fmt.Errorf(
-					"cannot read file %s in position [%d, %d): %w",
-					startPosition.Filename, startPosition.Offset, endPosition.Offset, err,
-				)
+				return nil, fmt.Errorf("cannot get type of field %s: %w", name.Name, err)
 			}
 
 			theField := &Field{
 				Name:               name.Name,
-				Type:               string(b),
+				Type:               fieldType,
+				BaseName:           name.Name,
+				Tag:                tag,
 				IsPublic:           IsPublic(name.Name),
-				WillGenerateGetter: willGenerateGetter,
-				WillGenerateSetter: willGenerateSetter,
+				Skip:               skip,
+				WillGenerateGetter: true,
+				WillGenerateSetter: true,
+				Pos:                p.position(name.Pos()),
+				tagOptions:         options,
 			}
 
-			if theField.IsPublic {
-				theField.WillGenerateGetter = false
-
-				fields[name.Name] = theField
-			} else {
-				theField.GetterName = toGetterName(name.Name)
-
-				fields[name.Name] = theField
+			if options.Name != "" {
+				theField.BaseName = options.Name
 			}
 
-			if theField.WillGenerateSetter {
-				theField.SetterName = toSetterName(name.Name)
+			if skip {
+				theField.skipGetter(nil, REASON_ANNOTATION_SKIP, "field is skipped by annotation")
+				theField.skipSetter(nil, REASON_ANNOTATION_SKIP, "field is skipped by annotation")
+			}
+			if theField.IsPublic {
+				theField.skipGetter(nil, REASON_PUBLIC_FIELD, "field is public")
 			}
+			if options.DisableGetter {
+				theField.skipGetter(nil, REASON_TAG_DISABLED, "getter is disabled by tag")
+			}
+			if options.DisableSetter {
+				theField.skipSetter(nil, REASON_TAG_DISABLED, "setter is disabled by tag")
+			}
+
+			fields[name.Name] = theField
 		}
 	}
 
 	return
 }
 
-func GetStructsFromFile(astFile *ast.File) (Structs, error) {
+func (p *Package) getStructsFromFile(astFile *ast.File) (Structs, error) {
 	structs := make(Structs, 0)
 
 	// 依赖的导入的内容
-	var f *os.File
-	var err error
 	importedStatementsBuilder := bytes.NewBuffer(make([]byte, 0, 128))
 	for _, decl := range astFile.Decls {
 		genDecl, ok := decl.(*ast.GenDecl)
@@ -134,27 +186,13 @@ func GetStructsFromFile(astFile *ast.File) (Structs, error) {
 			continue
 		}
 
-		startPos := fileSet.Position(genDecl.Pos())
-		endPos := fileSet.Position(genDecl.End())
-
-		if f == nil {
-			f, err = os.Open(startPos.Filename)
-			if err != nil {
-				return nil, fmt.Errorf("cannot read file %s: %w", startPos.Filename, err)
-			}
-			defer f.Close()
-		}
-
-		line := make([]byte, endPos.Offset-startPos.Offset)
-
-		_, _ = f.Seek(0, 0)
-		_, err := f.ReadAt(line, int64(startPos.Offset))
+		line, err := p.source(genDecl.Pos(), genDecl.End())
 
 		if err != nil {
-			return nil, fmt.Errorf("cannot read file %s in [%d, %d): %w", startPos.Filename, startPos.Offset, endPos.Offset, err)
+			return nil, fmt.Errorf("cannot get import statements: %w", err)
 		}
 
-		importedStatementsBuilder.Write(line)
+		importedStatementsBuilder.WriteString(line)
 		importedStatementsBuilder.WriteByte('\n')
 	}
 
@@ -188,16 +226,38 @@ func GetStructsFromFile(astFile *ast.File) (Structs, error) {
 					return nil, fmt.Errorf("cannot get shortName for %s: %w", name, err)
 				}
 
-				fields, err := GetFieldsFromStruct(structType)
+				// 单独的 type 定义的注释在 genDecl 上，type ( ... ) 中的在 typeSpec 上
+				annotations := p.getAnnotations(typeSpec.Doc)
+				if !genDecl.Lparen.IsValid() {
+					annotations = append(p.getAnnotations(genDecl.Doc), annotations...)
+				}
+
+				fields, err := p.getFieldsFromStruct(structType)
+
+				if err != nil {
+					return nil, fmt.Errorf("cannot get fields from struct %s: %w", name, err)
+				}
 
-				structs[name] = &Struct{
+				s := &Struct{
 					Name:               name,
 					ShortName:          shortName,
 					LowerName:          strings.ToLower(name),
 					IsPresent:          true,
 					Fields:             fields,
+					FieldList:          fields.List(p.Options.Sort),
+					Pos:                p.position(typeSpec.Pos()),
+					Package:            p,
+					Annotations:        annotations,
 					ImportedStatements: importedStatements,
 				}
+
+				err = DeriveMethodNames(s, p.Options)
+
+				if err != nil {
+					return nil, fmt.Errorf("cannot derive method names for struct %s: %w", name, err)
+				}
+
+				structs[name] = s
 			}
 		}
 	}
@@ -205,111 +265,122 @@ func GetStructsFromFile(astFile *ast.File) (Structs, error) {
 	return structs, nil
 }
 
-func GetStructsFromPackage() (Structs, error) {
-	pkgName := viper.GetString("gopackage")
-	if pkgName == "" {
-		return nil, fmt.Errorf("missing package name (gopackage config)")
+// DisableExistedMethods 检查已经存在的同名方法与同名字段，这些方法不会生成
+func DisableExistedMethods(s *Struct, functions Functions) {
+	var initialisms []string
+	if s.Package != nil {
+		initialisms = s.Package.Options.Initialisms
 	}
 
-	// 获取包中所有的 Go 文件
-	pkgInfo, err := build.ImportDir(".", 0)
-	if err != nil {
-		return nil, fmt.Errorf("cannot build from package: %w", err)
+	for _, field := range s.FieldList {
+		if field.WillGenerateGetter {
+			if function, ok := functions[field.GetterName]; ok {
+				field.GetterAlreadyExist = true
+				field.skipGetter(s, REASON_METHOD_EXISTS, "method %s already exists at %s", field.GetterName, function.Pos)
+			} else if other, ok := s.Fields[field.GetterName]; ok {
+				field.GetterAlreadyExist = true
+				skipForField(s, field, other, field.GetterName, field.skipGetter)
+			} else if function := findSimilarMethod(functions, field.GetterName, legacyMethodName(field, field.GetterName, initialisms)); function != nil {
+				field.GetterAlreadyExist = true
+				field.skipGetter(s, REASON_METHOD_CONFLICT, "method %s conflicts with existing method %s at %s", field.GetterName, function.Name, function.Pos)
+			}
+		}
+		if field.WillGenerateSetter {
+			if function, ok := functions[field.SetterName]; ok {
+				field.SetterAlreadyExist = true
+				field.skipSetter(s, REASON_METHOD_EXISTS, "method %s already exists at %s", field.SetterName, function.Pos)
+			} else if other, ok := s.Fields[field.SetterName]; ok {
+				field.SetterAlreadyExist = true
+				skipForField(s, field, other, field.SetterName, field.skipSetter)
+			} else if function := findSimilarMethod(functions, field.SetterName, legacyMethodName(field, field.SetterName, initialisms)); function != nil {
+				field.SetterAlreadyExist = true
+				field.skipSetter(s, REASON_METHOD_CONFLICT, "method %s conflicts with existing method %s at %s", field.SetterName, function.Name, function.Pos)
+			}
+		}
 	}
+}
 
-	// 检查传递的 gofile 是否在包中，不在则报错
-
-	filename := viper.GetString("gofile")
-	found := false
-
-	for _, existFilename := range pkgInfo.GoFiles {
-		if filename == existFilename {
-			found = true
-			break
-		}
+// findSimilarMethod 返回与 name 只有大小写不同、或者名称为 legacy 的方法，没有时返回 nil
+func findSimilarMethod(functions Functions, name, legacy string) *Function {
+	names := make([]string, 0, len(functions))
+	for functionName := range functions {
+		names = append(names, functionName)
 	}
+	sort.Strings(names)
 
-	if !found {
-		return nil, fmt.Errorf("cannot find provided filename %s", filename)
+	for _, functionName := range names {
+		if strings.EqualFold(functionName, name) || (legacy != "" && functionName == legacy) {
+			return functions[functionName]
+		}
 	}
 
-	fileSet = token.NewFileSet()
+	return nil
+}
 
-	// 检查是否传递了要设置 Getter 的列表，未设置则遍历当前文件的结构体定义来设置
-	structNames := viper.GetStringSlice("struct")
-	var structs Structs
+// legacyMethodName 返回不处理缩写词与分隔符时（只将字段名的首字母大写）推导出的方法名，例如 user_id 的 User_id，与 name 相同时返回空字符串
+func legacyMethodName(field *Field, name string, initialisms []string) string {
+	if field.BaseName == "" {
+		return ""
+	}
 
-	if len(structNames) == 0 { // 获取当前文件中的所有 struct
-		f, err := parser.ParseFile(fileSet, filename, nil, 0)
+	pascal := ToPascalName(field.BaseName, initialisms...)
+	runes := []rune(field.BaseName)
+	legacy := strings.ToUpper(string(runes[0])) + string(runes[1:])
 
-		if err != nil {
-			return nil, fmt.Errorf("cannot parse file %s: %w", filename, err)
-		}
+	if pascal == legacy || !strings.Contains(name, pascal) {
+		return ""
+	}
 
-		structs, err = GetStructsFromFile(f)
+	return strings.Replace(name, pascal, legacy, 1)
+}
 
-		if err != nil {
-			return nil, fmt.Errorf("cannot get structs from file %s: %w", filename, err)
-		}
+func skipForField(s *Struct, field, other *Field, method string, skip func(*Struct, Reason, string, ...interface{})) {
+	if strings.EqualFold(field.Name, other.Name) {
+		skip(s, REASON_SAME_NAME_FIELD, "field %s and %s differ only in case, method %s will not be generated", field.Name, other.Name, method)
 	} else {
-		structs = make(Structs, len(structNames))
-		for _, structName := range structNames {
-			structs[structName] = nil
-		}
-
-		for _, file := range pkgInfo.GoFiles {
-			f, err := parser.ParseFile(fileSet, file, nil, 0)
+		skip(s, REASON_FIELD_CONFLICT, "method %s conflicts with field %s", method, other.Name)
+	}
+}
 
-			if err != nil {
-				return nil, fmt.Errorf("cannot parse file %s: %w", filename, err)
-			}
+// DisableConflictedMethods 检查不同字段推导出的同名方法（例如 userId 与 userID 都会推导出 UserID），冲突的方法都不会生成
+func DisableConflictedMethods(s *Struct) {
+	owners := make(map[string][]*Field)
+	var methods []string
 
-			tempStructs, err := GetStructsFromFile(f)
-			if err != nil {
-				return nil, fmt.Errorf("cannot get structs from file %s: %w", file, err)
-			}
-
-			for tempStructName, tempStruct := range tempStructs {
-				if _, ok := structs[tempStructName]; ok {
-					structs[tempStructName] = tempStruct
-				}
+	for _, field := range s.FieldList {
+		if field.WillGenerateGetter {
+			if _, ok := owners[field.GetterName]; !ok {
+				methods = append(methods, field.GetterName)
 			}
+			owners[field.GetterName] = append(owners[field.GetterName], field)
 		}
-
-		for name, s := range structs {
-			if s == nil {
-				return nil, fmt.Errorf("cannot get struct %s from package", name)
+		if field.WillGenerateSetter {
+			if _, ok := owners[field.SetterName]; !ok {
+				methods = append(methods, field.SetterName)
 			}
+			owners[field.SetterName] = append(owners[field.SetterName], field)
 		}
 	}
 
-	// avoid to generate existed getter/setter
-	for _, s := range structs {
-		functions, err := GetFunctionsFromPackageForStruct(s)
+	for _, method := range methods {
+		fields := owners[method]
+		if len(fields) < 2 {
+			continue
+		}
 
-		if err != nil {
-			return nil, fmt.Errorf("cannot get functions from package: %w", err)
+		names := make([]string, 0, len(fields))
+		for _, field := range fields {
+			names = append(names, field.Name)
 		}
+		sort.Strings(names)
 
-		for _, field := range s.Fields {
-			if field.WillGenerateGetter {
-				if _, ok := functions[field.GetterName]; ok {
-					field.WillGenerateGetter = false
-				}
-				if _, ok := s.Fields[field.GetterName]; ok {
-					field.WillGenerateGetter = false
-				}
+		for _, field := range fields {
+			if field.WillGenerateGetter && field.GetterName == method {
+				field.skipGetter(s, REASON_NAME_CONFLICT, "method %s is derived from multiple fields: %s", method, strings.Join(names, ", "))
 			}
-			if field.WillGenerateSetter {
-				if _, ok := functions[field.SetterName]; ok {
-					field.WillGenerateSetter = false
-				}
-				if _, ok := s.Fields[field.SetterName]; ok {
-					field.WillGenerateSetter = false
-				}
+			if field.WillGenerateSetter && field.SetterName == method {
+				field.skipSetter(s, REASON_NAME_CONFLICT, "method %s is derived from multiple fields: %s", method, strings.Join(names, ", "))
 			}
 		}
 	}
-
-	return structs, nil
 }
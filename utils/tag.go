@@ -0,0 +1,117 @@
+package utils
+
+import (
+	"fmt"
+	"go/token"
+	"reflect"
+	"strings"
+)
+
+// TAG_NAME 是 god 使用的 struct tag 命名空间
+//
+// 语法为 `god:"key=value,key=value"`，支持的 key：
+//
+//	getter  Getter 的名称，值为 - 时不生成 Getter
+//	setter  Setter 的名称，值为 - 时不生成 Setter
+//	name    推导 Getter/Setter 名称时使用的字段名，例如 name=ID 会生成 ID 与 SetID
+const TAG_NAME = "god"
+
+// TagOptions 是从 struct tag 中解析出的字段选项
+type TagOptions struct {
+	Name string // 覆盖推导方法名时使用的字段名
+
+	GetterName    string // 自定义的 Getter 名称
+	DisableGetter bool
+
+	SetterName    string // 自定义的 Setter 名称
+	DisableSetter bool
+}
+
+// ParseTag 解析 struct tag（不含反引号），兼容旧的 `getter:"disable"` 与 `setter:"disable"` 写法
+func ParseTag(tag string) (*TagOptions, error) {
+	options := &TagOptions{}
+	structTag := reflect.StructTag(tag)
+
+	if value, ok := structTag.Lookup("getter"); ok {
+		disable, err := parseLegacyTag("getter", value)
+		if err != nil {
+			return nil, err
+		}
+
+		options.DisableGetter = disable
+	}
+
+	if value, ok := structTag.Lookup("setter"); ok {
+		disable, err := parseLegacyTag("setter", value)
+		if err != nil {
+			return nil, err
+		}
+
+		options.DisableSetter = disable
+	}
+
+	value, ok := structTag.Lookup(TAG_NAME)
+	if !ok {
+		return options, nil
+	}
+
+	for _, option := range strings.Split(value, ",") {
+		option = strings.TrimSpace(option)
+		if option == "" {
+			continue
+		}
+
+		eq := strings.IndexByte(option, '=')
+		if eq == -1 {
+			return nil, fmt.Errorf("option %q of %s tag must be in key=value form", option, TAG_NAME)
+		}
+
+		key, v := strings.TrimSpace(option[:eq]), strings.TrimSpace(option[eq+1:])
+		if v == "" {
+			return nil, fmt.Errorf("option %s of %s tag cannot be empty", key, TAG_NAME)
+		}
+
+		switch key {
+		case "getter":
+			if v == "-" {
+				options.DisableGetter = true
+			} else if token.IsIdentifier(v) {
+				options.GetterName = v
+			} else {
+				return nil, fmt.Errorf("getter name %q is not a valid identifier", v)
+			}
+		case "setter":
+			if v == "-" {
+				options.DisableSetter = true
+			} else if token.IsIdentifier(v) {
+				options.SetterName = v
+			} else {
+				return nil, fmt.Errorf("setter name %q is not a valid identifier", v)
+			}
+		case "name":
+			if !token.IsIdentifier(v) {
+				return nil, fmt.Errorf("name %q is not a valid identifier", v)
+			}
+
+			options.Name = v
+		default:
+			return nil, fmt.Errorf("unknown option %q in %s tag", key, TAG_NAME)
+		}
+	}
+
+	return options, nil
+}
+
+func parseLegacyTag(key, value string) (disable bool, err error) {
+	for _, option := range strings.Split(value, ",") {
+		switch strings.TrimSpace(option) {
+		case "disable":
+			disable = true
+		case "":
+		default:
+			return false, fmt.Errorf("unknown option %q in %s tag", option, key)
+		}
+	}
+
+	return disable, nil
+}
@@ -0,0 +1,53 @@
+package utils
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseTag(t *testing.T) {
+	cases := []struct {
+		name    string
+		tag     string
+		want    TagOptions
+		wantErr bool
+	}{
+		{name: "empty", tag: ``, want: TagOptions{}},
+		{name: "other tags", tag: `json:"name,omitempty"`, want: TagOptions{}},
+		{name: "rename", tag: `god:"getter=GetName,setter=ChangeName"`, want: TagOptions{GetterName: "GetName", SetterName: "ChangeName"}},
+		{name: "disable", tag: `god:"getter=-,setter=-"`, want: TagOptions{DisableGetter: true, DisableSetter: true}},
+		{name: "base name", tag: `god:"name=ID"`, want: TagOptions{Name: "ID"}},
+		{name: "spaces", tag: `god:" getter = Name , "`, want: TagOptions{GetterName: "Name"}},
+		{name: "legacy getter", tag: `getter:"disable"`, want: TagOptions{DisableGetter: true}},
+		{name: "legacy setter", tag: `setter:"disable"`, want: TagOptions{DisableSetter: true}},
+		{name: "legacy empty", tag: `getter:""`, want: TagOptions{}},
+		{name: "legacy with god", tag: `getter:"disable" god:"setter=Change"`, want: TagOptions{DisableGetter: true, SetterName: "Change"}},
+		{name: "legacy unknown option", tag: `getter:"disable,foo"`, wantErr: true},
+		{name: "missing value", tag: `god:"getter"`, wantErr: true},
+		{name: "empty value", tag: `god:"getter="`, wantErr: true},
+		{name: "invalid identifier", tag: `god:"setter=set-name"`, wantErr: true},
+		{name: "invalid name", tag: `god:"name=1d"`, wantErr: true},
+		{name: "unknown key", tag: `god:"foo=bar"`, wantErr: true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got, err := ParseTag(c.tag)
+
+			if c.wantErr {
+				if err == nil {
+					t.Fatalf("ParseTag(%q) = %+v, want error", c.tag, got)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("ParseTag(%q) returned error: %s", c.tag, err)
+			}
+
+			if !reflect.DeepEqual(*got, c.want) {
+				t.Errorf("ParseTag(%q) = %+v, want %+v", c.tag, *got, c.want)
+			}
+		})
+	}
+}
@@ -2,28 +2,29 @@ package utils
 
 import (
 	"bytes"
-	"log"
+	"fmt"
 	"text/template"
 )
 
-func GetTemplate(name string, text string) *template.Template {
-	t, err := template.New(name).Parse(text)
+// GetTemplate 解析模板，模板中可以使用 TemplateFuncs 中的函数
+func GetTemplate(name string, text string, options *Options) (*template.Template, error) {
+	t, err := template.New(name).Funcs(TemplateFuncs(options)).Parse(text)
 
 	if err != nil {
-		log.Fatalf("Cannot build template: name = %s; text = %s; err = %s", name, text, err)
+		return nil, fmt.Errorf("cannot parse %s template: %w", name, err)
 	}
 
-	return t
+	return t, nil
 }
 
-func ExecuteTemplate(tmpl *template.Template, data interface{}) string {
+func ExecuteTemplate(tmpl *template.Template, data interface{}) (string, error) {
 	b := bytes.NewBuffer(make([]byte, 0, 64))
 
 	err := tmpl.Execute(b, data)
 
 	if err != nil {
-		log.Fatalf("Cannot execute template: template = %#+v, err = %s", tmpl, err)
+		return "", fmt.Errorf("cannot execute %s template: %w", tmpl.Name(), err)
 	}
 
-	return b.String()
+	return b.String(), nil
 }
@@ -0,0 +1,356 @@
+package utils
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// GeneratedFile 是需要进行类型检查的生成的文件
+type GeneratedFile struct {
+	Filename  string  // 只使用文件名，目录由调用者决定
+	Content   []byte  // 格式化后的代码
+	Generator string  // 生成器，用于说明错误
+	Struct    *Struct // 对应的结构体，合并的文件为 nil
+
+	Replaces []string // 被这个文件替代、将被删除的文件
+}
+
+// TypeChecker 对生成的代码进行类型检查
+//
+// 依赖的包使用 go list -export 得到的导出数据（与 go build 共享构建缓存），而不是每次都从源码检查，
+// 导出数据与导入的包在所有检查之间共享，可以被并发地调用
+type TypeChecker struct {
+	fset *token.FileSet
+
+	mu       sync.Mutex        // 保护 exports 与 importer
+	exports  map[string]string // 包路径 -> 导出数据文件，为空表示没有导出数据
+	importer types.Importer
+}
+
+func NewTypeChecker() *TypeChecker {
+	c := &TypeChecker{
+		fset:    token.NewFileSet(),
+		exports: make(map[string]string),
+	}
+
+	c.importer = importer.ForCompiler(c.fset, "gc", c.lookup)
+
+	return c
+}
+
+// lookup 打开包的导出数据，调用时已经持有 mu
+func (c *TypeChecker) lookup(importPath string) (io.ReadCloser, error) {
+	filename := c.exports[importPath]
+	if filename == "" {
+		return nil, fmt.Errorf("cannot find export data of package %s", importPath)
+	}
+
+	return os.Open(filename)
+}
+
+// Import 实现 types.Importer
+func (c *TypeChecker) Import(importPath string) (*types.Package, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.importer.Import(importPath)
+}
+
+// resolve 在 workDir 中（为空时为当前目录）使用 go list 找到 imports 及其依赖的导出数据
+func (c *TypeChecker) resolve(workDir string, imports []string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	missing := make([]string, 0)
+	for _, importPath := range imports {
+		if _, ok := c.exports[importPath]; !ok {
+			missing = append(missing, importPath)
+		}
+	}
+
+	if len(missing) == 0 {
+		return nil
+	}
+
+	args := append([]string{"list", "-e", "-export", "-deps", "-f", "{{ .ImportPath }}\t{{ .Export }}", "--"}, missing...)
+	cmd := exec.Command("go", args...)
+	cmd.Dir = workDir
+
+	stderr := &bytes.Buffer{}
+	cmd.Stderr = stderr
+
+	output, err := cmd.Output()
+
+	if err != nil {
+		return fmt.Errorf("cannot list dependencies: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	for scanner.Scan() {
+		parts := strings.SplitN(scanner.Text(), "\t", 2)
+		if len(parts) == 2 {
+			c.exports[parts[0]] = parts[1]
+		}
+	}
+
+	// 找不到的包同样记录下来，错误在导入时报告
+	for _, importPath := range missing {
+		if _, ok := c.exports[importPath]; !ok {
+			c.exports[importPath] = ""
+		}
+	}
+
+	return scanner.Err()
+}
+
+// Check 对 fsys 中 dir 目录（使用 / 分隔的路径）的包进行类型检查，返回 overlay 中的类型错误，
+// workDir 是用于解析 import 的磁盘目录，为空时为当前目录
+//
+// overlay 中的文件（dir 中的文件名 -> 内容）会替换包中的同名文件或者作为新的文件加入包中，内容为 nil 表示文件将被删除。
+// 包中其他的文件（包括 god 之前生成的文件）都会参与检查，与生成的代码重复定义的函数会作为生成的代码中的错误返回
+func (c *TypeChecker) Check(fsys fs.FS, dir, workDir string, overlay map[string][]byte) ([]types.Error, error) {
+	pkgInfo, err := buildContext(fsys).ImportDir(dir, 0)
+
+	if err != nil {
+		return nil, fmt.Errorf("cannot build from package %s: %w", dir, err)
+	}
+
+	files := make([]*ast.File, 0, len(pkgInfo.GoFiles)+len(overlay))
+
+	for _, name := range append(pkgInfo.GoFiles, pkgInfo.CgoFiles...) {
+		if _, ok := overlay[name]; ok {
+			continue
+		}
+
+		filename := path.Join(dir, name)
+		src, err := fs.ReadFile(fsys, filename)
+
+		if err != nil {
+			return nil, fmt.Errorf("cannot read file %s: %w", filename, err)
+		}
+
+		f, err := parser.ParseFile(c.fset, filename, src, 0)
+
+		if err != nil {
+			return nil, fmt.Errorf("cannot parse file %s: %w", filename, err)
+		}
+
+		files = append(files, f)
+	}
+
+	names := make([]string, 0, len(overlay))
+	for name, src := range overlay {
+		if src != nil {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	// 生成的文件放在最后，重复定义的错误会出现在生成的文件中
+	overlaid := make(map[string]bool, len(names))
+
+	for _, name := range names {
+		filename := path.Join(dir, name)
+		f, err := parser.ParseFile(c.fset, filename, overlay[name], 0)
+
+		if err != nil {
+			return nil, fmt.Errorf("cannot parse file %s: %w", name, err)
+		}
+
+		overlaid[filename] = true
+		files = append(files, f)
+	}
+
+	err = c.resolve(workDir, importPaths(files))
+
+	if err != nil {
+		return nil, err
+	}
+
+	// 无法导入的包会被当作空的包，之后使用它的代码都不会报错，因此导入失败时检查没有意义
+	importErrs := make(Errors, 0)
+	importer := importerFunc(func(importPath string) (*types.Package, error) {
+		pkg, err := c.Import(importPath)
+
+		if err != nil {
+			importErrs = append(importErrs, fmt.Errorf("cannot import %s: %w", importPath, err))
+		}
+
+		return pkg, err
+	})
+
+	errs := make([]types.Error, 0)
+	config := types.Config{
+		Importer:    importer,
+		FakeImportC: true,
+		Error: func(err error) {
+			// 只关心生成的代码中的错误
+			if err, ok := err.(types.Error); ok && overlaid[err.Fset.Position(err.Pos).Filename] {
+				errs = append(errs, err)
+			}
+		},
+	}
+
+	// 错误通过 Error 收集
+	_, _ = config.Check(pkgInfo.Name, c.fset, files, nil)
+
+	if err := importErrs.ErrorOrNil(); err != nil {
+		return nil, err
+	}
+
+	return errs, nil
+}
+
+type importerFunc func(importPath string) (*types.Package, error)
+
+func (f importerFunc) Import(importPath string) (*types.Package, error) {
+	return f(importPath)
+}
+
+// CheckFiles 将 files 叠加到 fsys 中 dir 目录的包中进行类型检查（参数与 Check 相同），
+// 无法编译时返回每个错误，并说明产生错误的生成器、结构体、方法与字段
+func (c *TypeChecker) CheckFiles(fsys fs.FS, dir, workDir string, files []*GeneratedFile) error {
+	overlay := make(map[string][]byte)
+	generated := make(map[string]*GeneratedFile)
+
+	for _, file := range files {
+		name := filepath.Base(file.Filename)
+		overlay[name] = file.Content
+		generated[path.Join(dir, name)] = file
+
+		for _, filename := range file.Replaces {
+			overlay[filepath.Base(filename)] = nil
+		}
+	}
+
+	typeErrors, err := c.Check(fsys, dir, workDir, overlay)
+
+	if err != nil {
+		return fmt.Errorf("cannot type-check generated code: %w", err)
+	}
+
+	errs := make(Errors, 0, len(typeErrors))
+	for _, e := range typeErrors {
+		file := generated[e.Fset.Position(e.Pos).Filename]
+		errs = append(errs, DescribeTypeError(e, file.Filename, file.Content, file.Generator, file.Struct))
+	}
+
+	return errs.ErrorOrNil()
+}
+
+// importPaths 返回文件中所有需要导出数据的 import
+func importPaths(files []*ast.File) []string {
+	seen := make(map[string]bool)
+	paths := make([]string, 0)
+
+	for _, f := range files {
+		for _, spec := range f.Imports {
+			importPath, err := strconv.Unquote(spec.Path.Value)
+			if err != nil || importPath == "C" || importPath == "unsafe" || seen[importPath] {
+				continue
+			}
+
+			seen[importPath] = true
+			paths = append(paths, importPath)
+		}
+	}
+
+	sort.Strings(paths)
+
+	return paths
+}
+
+// declaredFuncs 返回文件中定义的函数，方法为 Type.Name
+func declaredFuncs(f *ast.File) []string {
+	names := make([]string, 0)
+
+	for _, decl := range f.Decls {
+		if decl, ok := decl.(*ast.FuncDecl); ok {
+			names = append(names, funcName(decl))
+		}
+	}
+
+	return names
+}
+
+func declaresAny(f *ast.File, names map[string]bool) bool {
+	for _, name := range declaredFuncs(f) {
+		if names[name] {
+			return true
+		}
+	}
+
+	return false
+}
+
+func funcName(decl *ast.FuncDecl) string {
+	if decl.Recv == nil || len(decl.Recv.List) == 0 {
+		return decl.Name.Name
+	}
+
+	return GetReceiverTypeName(decl.Recv.List[0].Type) + "." + decl.Name.Name
+}
+
+// DescribeTypeError 返回生成的代码中的类型错误，说明产生错误的生成器、结构体（合并的文件为 nil）与字段
+//
+// filename 与 src 为生成的文件的名称与内容
+func DescribeTypeError(e types.Error, filename string, src []byte, generator string, s *Struct) error {
+	position := e.Fset.Position(e.Pos)
+	position.Filename = filename
+
+	parts := []string{"generated by " + generator}
+	if s != nil {
+		parts[0] += " for struct " + s.Name
+	}
+
+	if fn := EnclosingFunc(src, position.Line); fn != "" {
+		parts = append(parts, "in "+fn)
+
+		if s != nil {
+			if field := s.FieldOfMethod(fn[strings.LastIndex(fn, ".")+1:]); field != nil {
+				parts = append(parts, "for field "+field.Name)
+			}
+		}
+	}
+
+	return fmt.Errorf("%s: %s (%s)", position, e.Msg, strings.Join(parts, ", "))
+}
+
+// EnclosingFunc 返回 src 中包含 line 行的函数，方法为 Type.Name，不在函数中时返回空字符串
+func EnclosingFunc(src []byte, line int) string {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "", src, 0)
+
+	if err != nil {
+		return ""
+	}
+
+	for _, decl := range f.Decls {
+		decl, ok := decl.(*ast.FuncDecl)
+		if !ok {
+			continue
+		}
+
+		if fset.Position(decl.Pos()).Line <= line && line <= fset.Position(decl.End()).Line {
+			return funcName(decl)
+		}
+	}
+
+	return ""
+}
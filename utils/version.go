@@ -0,0 +1,4 @@
+package utils
+
+// VERSION 是 god 的版本，会作为生成缓存的输入之一
+const VERSION = "0.1.0"
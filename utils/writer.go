@@ -0,0 +1,124 @@
+package utils
+
+import (
+	"archive/zip"
+	"fmt"
+	"io"
+	"io/fs"
+	"path/filepath"
+	"sync"
+)
+
+// Writer 保存生成的文件，所有的实现都可以被并发地调用
+type Writer interface {
+	WriteFile(filename string, content []byte) error
+}
+
+// DiskWriter 将文件写入磁盘
+type DiskWriter struct{}
+
+func (DiskWriter) WriteFile(filename string, content []byte) error {
+	return SaveToFile(filename, content)
+}
+
+// MemoryWriter 将文件保存在内存中
+type MemoryWriter struct {
+	mu    sync.Mutex
+	files map[string][]byte
+}
+
+func NewMemoryWriter() *MemoryWriter {
+	return &MemoryWriter{files: make(map[string][]byte)}
+}
+
+func (w *MemoryWriter) WriteFile(filename string, content []byte) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.files[filepath.ToSlash(filename)] = append([]byte(nil), content...)
+
+	return nil
+}
+
+// Files 返回已经写入的文件，key 为使用 / 分隔的文件名
+func (w *MemoryWriter) Files() map[string][]byte {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	files := make(map[string][]byte, len(w.files))
+	for filename, content := range w.files {
+		files[filename] = content
+	}
+
+	return files
+}
+
+// FS 以 fs.FS 的形式返回已经写入的文件，可以与输入叠加后再次加载
+func (w *MemoryWriter) FS() fs.FS {
+	return memoryFS(w.Files())
+}
+
+// ZipWriter 将文件写入 zip 压缩包，所有文件写入后需要调用 Close
+type ZipWriter struct {
+	mu sync.Mutex
+	zw *zip.Writer
+}
+
+func NewZipWriter(w io.Writer) *ZipWriter {
+	return &ZipWriter{zw: zip.NewWriter(w)}
+}
+
+func (w *ZipWriter) WriteFile(filename string, content []byte) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	f, err := w.zw.Create(filepath.ToSlash(filename))
+
+	if err != nil {
+		return fmt.Errorf("cannot add file %s to zip: %w", filename, err)
+	}
+
+	_, err = f.Write(content)
+
+	return err
+}
+
+func (w *ZipWriter) Close() error {
+	return w.zw.Close()
+}
+
+// StreamWriter 将文件依次输出到 w（例如 stdout），Header 为 true 时在每个文件前输出文件名
+type StreamWriter struct {
+	Header bool
+
+	mu    sync.Mutex
+	w     io.Writer
+	count int
+}
+
+func NewStreamWriter(w io.Writer) *StreamWriter {
+	return &StreamWriter{Header: true, w: w}
+}
+
+func (w *StreamWriter) WriteFile(filename string, content []byte) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.Header {
+		if w.count != 0 {
+			if _, err := fmt.Fprintln(w.w); err != nil {
+				return err
+			}
+		}
+
+		if _, err := fmt.Fprintf(w.w, "// %s\n", filename); err != nil {
+			return err
+		}
+	}
+
+	w.count++
+
+	_, err := w.w.Write(content)
+
+	return err
+}
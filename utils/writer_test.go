@@ -0,0 +1,42 @@
+package utils
+
+import (
+	"io/fs"
+	"testing"
+	"testing/fstest"
+)
+
+func TestMemoryWriterFS(t *testing.T) {
+	w := NewMemoryWriter()
+
+	files := map[string]string{
+		"user.go":              "package model\n",
+		"model/user_getter.go": "package model\n\nfunc (u *User) Name() string { return u.name }\n",
+		"model/sub/empty.go":   "",
+	}
+	for filename, content := range files {
+		if err := w.WriteFile(filename, []byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	fsys := w.FS()
+
+	if err := fstest.TestFS(fsys, "user.go", "model/user_getter.go", "model/sub/empty.go"); err != nil {
+		t.Fatal(err)
+	}
+
+	for filename, content := range files {
+		got, err := fs.ReadFile(fsys, filename)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != content {
+			t.Errorf("content of %s = %q, want %q", filename, got, content)
+		}
+	}
+
+	if _, err := fs.Stat(fsys, "missing.go"); err == nil {
+		t.Errorf("Stat(missing.go) succeeded, want error")
+	}
+}